@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/bilibili"
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/config"
+	"bilibili_subtitle/internal/subtitles"
+	"bilibili_subtitle/internal/utils"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// batchSources 是 batch 命令支持的视频列表，以及每种列表需要的 ID
+var batchSources = map[string][]string{
+	"fav":    {"media_id"},
+	"season": {"mid", "season_id"},
+	"series": {"mid", "series_id"},
+	"up":     {"mid"},
+}
+
+// runBatch 列出收藏夹、合集、系列或 UP 主投稿中的视频，逐个下载字幕、分析并保存结果。
+// 没有字幕的视频会被跳过并在最后列出
+func runBatch(args []string) int {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	lang := fs.String("lang", "", "subtitle language (e.g. zh, en, ai-zh); prefers human Chinese subtitles when empty")
+	outDir := fs.String("o", ".", "directory for the downloaded subtitles and the analysis results")
+	limit := fs.Int("limit", 0, "maximum number of videos taken from the list; 0 takes all of them")
+	list := fs.Bool("list", false, "list the videos instead of processing them")
+	firstPart := fs.Bool("first-part", false, "only process the first part of videos with several parts")
+	delay := fs.Duration("delay", 2*time.Second, "pause between videos to stay below Bilibili's rate limits")
+	clientChoice := fs.String("client", "gemini", "model used for the analysis: gemini or openai (the other one is the fallback)")
+	apiBase := fs.String("api", "", "Bilibili API base URL; uses the configured URL when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s batch [flags] fav <media_id>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s batch [flags] season <mid> <season_id>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s batch [flags] series <mid> <series_id>\n", os.Args[0])
+		fmt.Fprintf(fs.Output(), "       %s batch [flags] up <mid>\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	source := fs.Arg(0)
+	idNames, ok := batchSources[source]
+	if !ok || fs.NArg() != len(idNames)+1 {
+		fs.Usage()
+		return 2
+	}
+	ids := make([]int64, len(idNames))
+	for i, name := range idNames {
+		id, err := strconv.ParseInt(fs.Arg(i+1), 10, 64)
+		if err != nil || id <= 0 {
+			fmt.Fprintf(os.Stderr, "batch: invalid %s %q\n", name, fs.Arg(i+1))
+			return 2
+		}
+		ids[i] = id
+	}
+
+	cfg := config.NewConfig()
+	if *apiBase != "" {
+		cfg.BilibiliConfig.BaseURL = *apiBase
+	}
+	client, err := newBilibiliClient(cfg.BilibiliConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	var items []bilibili.ListItem
+	switch source {
+	case "fav":
+		items, err = client.FavoriteVideos(ctx, ids[0], *limit)
+	case "season":
+		items, err = client.SeasonVideos(ctx, ids[0], ids[1], *limit)
+	case "series":
+		items, err = client.SeriesVideos(ctx, ids[0], ids[1], *limit)
+	case "up":
+		items, err = client.UploaderVideos(ctx, ids[0], *limit)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+	if *list {
+		for _, item := range items {
+			fmt.Printf("%s  %s\n", item.BVID, item.Title)
+		}
+		return 0
+	}
+
+	if err := utils.SetProxy(); err != nil {
+		fmt.Fprintf(os.Stderr, "batch: failed to set proxy: %v\n", err)
+		return 1
+	}
+	if err := os.MkdirAll(*outDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+		return 1
+	}
+
+	var processed int
+	var skipped, failed []string
+	for i, item := range items {
+		if i > 0 {
+			time.Sleep(*delay)
+		}
+		fmt.Printf("[%d/%d] %s %s\n", i+1, len(items), item.BVID, item.Title)
+		video, err := client.Video(ctx, bvid.Ref{BVID: item.BVID, AID: item.AID})
+		if errors.Is(err, bilibili.ErrSessionExpired) {
+			return abortBatch(err, processed)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+			failed = append(failed, fmt.Sprintf("%s %s: %v", item.BVID, item.Title, err))
+			continue
+		}
+		// fetchTags 只在登录凭据失效时返回错误
+		tags, err := fetchTags(ctx, client, video)
+		if err != nil {
+			return abortBatch(err, processed)
+		}
+		pages := video.Pages
+		if *firstPart && len(pages) > 1 {
+			pages = pages[:1]
+		}
+		for _, page := range pages {
+			label := fmt.Sprintf("%s P%d %s", video.BVID, page.Page, video.Title)
+			output := filepath.Join(*outDir, subtitleFileName(video.BVID, page.Page, subtitles.FormatBCC))
+			filePath, err := savePageSubtitle(ctx, client, video, page, tags, *lang, output, subtitles.FormatBCC)
+			if errors.Is(err, bilibili.ErrNoSubtitles) {
+				fmt.Printf("  skipped: no subtitles\n")
+				skipped = append(skipped, label)
+				continue
+			}
+			if errors.Is(err, bilibili.ErrSessionExpired) {
+				return abortBatch(err, processed)
+			}
+			if err == nil {
+				err = processSubtitles(filePath, *clientChoice, cfg)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "batch: %s: %v\n", label, err)
+				failed = append(failed, fmt.Sprintf("%s: %v", label, err))
+				continue
+			}
+			processed++
+		}
+	}
+
+	fmt.Printf("\n%d analysed, %d skipped without subtitles, %d failed\n", processed, len(skipped), len(failed))
+	for _, label := range skipped {
+		fmt.Printf("  no subtitles: %s\n", label)
+	}
+	for _, message := range failed {
+		fmt.Printf("  failed: %s\n", message)
+	}
+	if len(failed) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// abortBatch 在登录凭据失效时停止批处理：之后的每个视频都会以同样的原因失败
+func abortBatch(err error, processed int) int {
+	fmt.Fprintf(os.Stderr, "batch: %v\n", err)
+	fmt.Fprintf(os.Stderr, "batch: stopped after %d analysed; re-export cookies.txt from a logged-in browser (BILIBILI_COOKIE_FILE) or update BILIBILI_SESSDATA, then run the batch again\n", processed)
+	return 1
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// command 是一个子命令，run 返回进程退出码
+type command struct {
+	usage string
+	run   func(args []string) int
+}
+
+// commands 列出所有子命令；不带子命令时进入交互式分析流程
+var commands = map[string]command{
+	"batch":    {"download and analyse every video of a favourites folder, season, series or uploader", runBatch},
+	"convert":  {"convert a subtitle file to srt, vtt, ass, bcc json or txt", runConvert},
+	"fetch":    {"download the subtitles of a Bilibili video by BV/AV id or URL", runFetch},
+	"lint":     {"check subtitles for overlaps, timing, reading speed and line length problems", runLint},
+	"merge":    {"merge the subtitles of several parts (P1...Pn) into one file", runMerge},
+	"retime":   {"shift, scale or resync subtitle timing and write it back in the source format", runRetime},
+	"split":    {"split a bilingual subtitle file into one file per language", runSplit},
+	"stats":    {"print speech time, speech rate, pauses and frequent terms without calling a model", runStats},
+	"validate": {"check subtitle files and print parser warnings", runValidate},
+}
+
+// printUsage 输出主流程的参数以及子命令列表
+func printUsage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags]\n       %s <command> [flags] [args]\n\nFlags:\n", os.Args[0], os.Args[0])
+	flag.PrintDefaults()
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(out, "\nCommands:\n")
+	for _, name := range names {
+		fmt.Fprintf(out, "  %-10s %s\n", name, commands[name].usage)
+	}
+}
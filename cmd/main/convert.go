@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runConvert 把字幕文件转换为另一种格式
+func runConvert(args []string) int {
+	fs := flag.NewFlagSet("convert", flag.ExitOnError)
+	format := fs.String("to", "", "output format: srt, vtt, ass, bcc, json (legacy) or txt; inferred from the output extension when empty")
+	encoding := fs.String("encoding", "", "character encoding of the input file; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s convert [flags] input output\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+
+	if err := subtitles.ConvertFile(fs.Arg(0), fs.Arg(1), *format, subtitles.ParseOptions{Encoding: *encoding}); err != nil {
+		fmt.Fprintf(os.Stderr, "convert: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/bilibili"
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/config"
+	"bilibili_subtitle/internal/metadata"
+	"bilibili_subtitle/internal/subtitles"
+	"bilibili_subtitle/internal/utils"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// runFetch 根据 BV/AV 号或视频链接下载 B 站字幕
+func runFetch(args []string) int {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	lang := fs.String("lang", "", "subtitle language (e.g. zh, en, ai-zh); prefers human Chinese subtitles when empty")
+	list := fs.Bool("list", false, "list the subtitle tracks instead of downloading")
+	output := fs.String("o", "", "output file; <BV id>[_p<n>].json in the current directory when empty")
+	format := fs.String("to", "", "output format: srt, vtt, ass, bcc, json (legacy) or txt; inferred from the output extension when empty")
+	apiBase := fs.String("api", "", "Bilibili API base URL; uses the configured URL when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s fetch [flags] BV-id|av-id|url|b23.tv-link\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	cfg := config.NewConfig().BilibiliConfig
+	if *apiBase != "" {
+		cfg.BaseURL = *apiBase
+	}
+	client, err := newBilibiliClient(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 1
+	}
+	ctx := context.Background()
+
+	ref, err := bvid.Resolve(ctx, client.HTTPClient, fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 2
+	}
+
+	if *list {
+		video, err := client.Video(ctx, ref)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+			return 1
+		}
+		page, err := video.Page(ref.Page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+			return 1
+		}
+		tracks, err := client.Subtitles(ctx, video, page)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%s P%d %s\n", video.BVID, page.Page, video.Title)
+		for _, track := range tracks {
+			kind := "human"
+			if track.AI() {
+				kind = "AI"
+			}
+			fmt.Printf("  %-8s %-6s %s\n", track.Lang, kind, track.LangName)
+		}
+		return 0
+	}
+
+	path, err := fetchSubtitleFile(ctx, client, ref, *lang, *output, *format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fetch: %v\n", err)
+		return 1
+	}
+	fmt.Println(path)
+	return 0
+}
+
+// newBilibiliClient 创建 B 站客户端，配置了 cookies.txt 时读取其中的登录凭据，
+// 并在日志中隐藏凭据
+func newBilibiliClient(cfg config.BilibiliConfig) (*bilibili.Client, error) {
+	client := bilibili.NewClient(cfg)
+	if cfg.CookieFile != "" {
+		cookies, err := bilibili.LoadCookieFile(cfg.CookieFile)
+		if err != nil {
+			return nil, err
+		}
+		client.SetCookies(cookies)
+	}
+	log.SetOutput(utils.NewRedactWriter(os.Stderr, client.Secrets()...))
+	return client, nil
+}
+
+// fetchSubtitleFile 下载视频字幕并写入 output，返回写入的文件路径，视频信息另存为旁边的 .meta.json 文件。
+// output 为空时写入当前目录下以 BV 号命名的 BCC JSON 文件
+func fetchSubtitleFile(ctx context.Context, client *bilibili.Client, ref bvid.Ref, lang, output, format string) (string, error) {
+	video, err := client.Video(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	page, err := video.Page(ref.Page)
+	if err != nil {
+		return "", err
+	}
+	tags, err := fetchTags(ctx, client, video)
+	if err != nil {
+		return "", err
+	}
+	return savePageSubtitle(ctx, client, video, page, tags, lang, output, format)
+}
+
+// fetchTags 查询视频标签；标签只用于提示词，查询失败时只记录警告，不影响字幕下载。
+// 登录凭据失效时返回 bilibili.ErrSessionExpired，之后的请求也会失败
+func fetchTags(ctx context.Context, client *bilibili.Client, video *bilibili.Video) ([]string, error) {
+	tags, err := client.Tags(ctx, bvid.Ref{BVID: video.BVID, AID: video.AID})
+	if errors.Is(err, bilibili.ErrSessionExpired) {
+		return nil, err
+	}
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return tags, nil
+}
+
+// savePageSubtitle 下载已查询到的视频中一个分 P 的字幕并写入 output，视频信息和 tags 另存为旁边的 .meta.json 文件。
+// output 为空时的处理与 fetchSubtitleFile 相同
+func savePageSubtitle(ctx context.Context, client *bilibili.Client, video *bilibili.Video, page bilibili.Page, tags []string, lang, output, format string) (string, error) {
+	transcript, _, err := client.FetchPageTranscript(ctx, video, page, lang)
+	if err != nil {
+		return "", err
+	}
+	if output == "" {
+		if format == "" {
+			format = subtitles.FormatBCC
+		}
+		output = subtitleFileName(video.BVID, page.Page, format)
+	}
+	if err := subtitles.WriteSubtitleFile(output, format, transcript); err != nil {
+		return "", err
+	}
+	if err := video.Metadata(page, tags).Save(metadata.SidecarPath(output)); err != nil {
+		return "", err
+	}
+	return filepath.Clean(output), nil
+}
+
+// subtitleFileName 返回下载字幕时默认使用的文件名 "<BV 号>[_p<n>].<格式扩展名>"
+func subtitleFileName(bvid string, page int, format string) string {
+	name := bvid
+	if page > 1 {
+		name += fmt.Sprintf("_p%d", page)
+	}
+	return name + formatExt(format)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// lintResult 是一个文件的检查结果，用于 JSON 输出
+type lintResult struct {
+	File   string                `json:"file"`
+	Format string                `json:"format,omitempty"`
+	Cues   int                   `json:"cues"`
+	Error  string                `json:"error,omitempty"`
+	Issues []subtitles.LintIssue `json:"issues"`
+}
+
+// runLint 检查字幕文件的质量问题。
+// 退出码：0 没有问题，1 只有警告，2 存在错误或文件无法解析
+func runLint(args []string) int {
+	opts := subtitles.DefaultLintOptions
+	fs := flag.NewFlagSet("lint", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the results as JSON")
+	ignore := fs.String("ignore", "", "comma-separated rules to skip: parse, empty, duration, overlap, gap, order, cps, line-length")
+	fs.Float64Var(&opts.MaxCJKCPS, "max-cps-cjk", opts.MaxCJKCPS, "maximum characters per second for Chinese, Japanese and Korean text")
+	fs.Float64Var(&opts.MaxLatinCPS, "max-cps-latin", opts.MaxLatinCPS, "maximum characters per second for other text")
+	fs.IntVar(&opts.MaxCJKLine, "max-line-cjk", opts.MaxCJKLine, "maximum characters per line for Chinese, Japanese and Korean text")
+	fs.IntVar(&opts.MaxLatinLine, "max-line-latin", opts.MaxLatinLine, "maximum characters per line for other text")
+	fs.DurationVar(&opts.MinGap, "min-gap", opts.MinGap, "minimum gap between cues that do not touch")
+	encoding := fs.String("encoding", "", "character encoding of the subtitle files; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s lint [flags] file...\n\nExit status is 0 when no issues are found, 1 when there are only warnings\nand 2 when there are errors or a file cannot be parsed.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	if *ignore != "" {
+		opts.Ignore = strings.Split(*ignore, ",")
+	}
+
+	exitCode := 0
+	results := make([]lintResult, 0, fs.NArg())
+	for _, filePath := range fs.Args() {
+		result := lintResult{File: filePath, Issues: []subtitles.LintIssue{}}
+		transcript, err := subtitles.ParseSubtitleFileWithOptions(filePath, subtitles.ParseOptions{Encoding: *encoding})
+		if err != nil {
+			result.Error = err.Error()
+			exitCode = 2
+		} else {
+			result.Format, result.Cues = transcript.Format, len(transcript.Cues)
+			if issues := subtitles.Lint(transcript, opts); len(issues) > 0 {
+				result.Issues = issues
+				if subtitles.HasErrors(issues) {
+					exitCode = 2
+				} else if exitCode == 0 {
+					exitCode = 1
+				}
+			}
+		}
+		results = append(results, result)
+
+		if !*asJSON {
+			printLintResult(result)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		if err := encoder.Encode(results); err != nil {
+			fmt.Fprintf(os.Stderr, "lint: %v\n", err)
+			return 2
+		}
+	}
+	return exitCode
+}
+
+// printLintResult 以 "文件: 位置: 级别: 描述 [规则]" 的形式输出问题
+func printLintResult(result lintResult) {
+	if result.Error != "" {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", result.File, result.Error)
+		return
+	}
+	errors := 0
+	for _, issue := range result.Issues {
+		fmt.Printf("%s: %s\n", result.File, issue)
+		if issue.Severity == subtitles.SeverityError {
+			errors++
+		}
+	}
+	fmt.Printf("%s: %s, %d cues, %d errors, %d warnings\n", result.File, result.Format, result.Cues, errors, len(result.Issues)-errors)
+}
@@ -2,32 +2,75 @@ package main
 
 import (
 	"bilibili_subtitle/internal/api"
+	"bilibili_subtitle/internal/bvid"
 	"bilibili_subtitle/internal/config"
+	"bilibili_subtitle/internal/metadata"
+	"bilibili_subtitle/internal/normalize"
+	"bilibili_subtitle/internal/stats"
 	"bilibili_subtitle/internal/subtitles"
 	"bilibili_subtitle/internal/summarization"
 	"bilibili_subtitle/internal/utils"
 	"context"
+	"flag"
 	"fmt"
 	"github.com/sqweek/dialog"
 	"log"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
+)
+
+var (
+	videoFlag      = flag.String("video", "", "Bilibili BV/AV id, video URL or b23.tv link; downloads its subtitles into the current directory instead of opening a file dialog")
+	subtitleLang   = flag.String("lang", "", "subtitle language to download with -video (e.g. zh, en); prefers human Chinese subtitles when empty")
+	encodingName   = flag.String("encoding", "", "character encoding of the subtitle file (e.g. gbk, big5, utf-16le); detected automatically when empty")
+	partsFlag      = flag.String("parts", "", "comma-separated subtitle files of the following parts (P2...Pn), merged after the selected file and analysed together")
+	partOffsets    = flag.String("part-offsets", "", "comma-separated start time of each part including the first (e.g. 0,25:30); parts are placed back to back when empty")
+	track          = flag.String("track", "", "language track to analyse in bilingual subtitles (e.g. zh or en); all lines are kept when empty")
+	excludeSigns   = flag.Bool("exclude-signs", false, "exclude ASS signs and karaoke lines from the analysed text")
+	danmakuPath    = flag.String("danmaku", "", "XML danmaku file to analyse together with the subtitles")
+	danmakuSection = flag.Duration("danmaku-section", time.Minute, "length of each section when reporting danmaku reactions")
+	danmakuTop     = flag.Int("danmaku-top", 20, "number of most frequent danmaku listed per section")
+	musicPolicy    = flag.String("music", "", "what to do with lyrics in BCC subtitles: tag, drop or keep; uses the configured policy when empty")
+	normalizeFlag  = flag.String("normalize", "", "comma-separated normalisation passes applied before analysis, or \"none\"; uses the configured passes when empty")
+	rawText        = flag.Bool("raw-text", false, "join cues with commas instead of segmenting them into sentences and paragraphs")
+	zhFlag         = flag.String("zh", "", "Simplified/Traditional conversion applied to the subtitles before analysis (s2t, s2tw, s2twp, s2hk, t2s, tw2s, tw2sp, hk2s)")
+	zhOutputFlag   = flag.String("zh-output", "", "Simplified/Traditional conversion applied to the generated analysis")
+	metaFlag       = flag.String("meta", "", "JSON file with the video title, uploader, description and tags (yt-dlp .info.json works); a .meta.json or .info.json next to the subtitle file is used when empty")
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(cmd.run(os.Args[2:]))
+		}
+	}
+	flag.Usage = printUsage
+	flag.Parse()
+
 	//Set proxy (from utils)
 	if err := utils.SetProxy(); err != nil {
 		log.Fatal("Failed to set proxy:", err)
 	}
 
-	filePath, err := openFileDialog()
-	handleError(err, "Failed to open file dialog")
+	cfg := config.NewConfig()
+
+	var filePath string
+	var err error
+	if *videoFlag != "" {
+		filePath, err = fetchVideo(*videoFlag, cfg)
+		handleError(err, "Failed to download subtitles")
+	} else {
+		filePath, err = openFileDialog()
+		handleError(err, "Failed to open file dialog")
+	}
 
 	if filePath == "" {
 		log.Fatal("No file selected.")
 		return
 	}
 
-	cfg := config.NewConfig()
 	clientChoice := "gemini"
 	//clientChoice := "openai"
 
@@ -41,7 +84,21 @@ func main() {
 }
 
 func openFileDialog() (string, error) {
-	return dialog.File().Filter("JSON ,SRT and txt files", "json", "srt", "txt").Load()
+	return dialog.File().Filter("JSON ,SRT ,VTT ,ASS and txt files", "json", "srt", "vtt", "ass", "ssa", "txt").Load()
+}
+
+// fetchVideo 下载 -video 指定的视频字幕，返回保存的 BCC JSON 文件路径
+func fetchVideo(video string, cfg *config.Config) (string, error) {
+	ctx := context.Background()
+	client, err := newBilibiliClient(cfg.BilibiliConfig)
+	if err != nil {
+		return "", err
+	}
+	ref, err := bvid.Resolve(ctx, client.HTTPClient, video)
+	if err != nil {
+		return "", err
+	}
+	return fetchSubtitleFile(ctx, client, ref, *subtitleLang, "", "")
 }
 
 func handleError(err error, msg string) {
@@ -51,11 +108,82 @@ func handleError(err error, msg string) {
 }
 
 func processSubtitles(filePath string, clientChoice string, cfg *config.Config) error {
-	// 解析字幕文件
-	parsedText, err := subtitles.ParseSubtitleFile(filePath)
+	// 解析字幕文件，指定了 -parts 时按顺序合并各个分 P
+	parseOpts := subtitles.ParseOptions{Encoding: *encodingName}
+	var transcript *subtitles.Transcript
+	var err error
+	if *partsFlag != "" {
+		files := append([]string{filePath}, strings.Split(*partsFlag, ",")...)
+		transcript, err = mergeParts(files, *partOffsets, 0, parseOpts)
+	} else {
+		transcript, err = subtitles.ParseSubtitleFileWithOptions(filePath, parseOpts)
+	}
+	if err != nil {
+		return err
+	}
+	if *track != "" {
+		if transcript, err = subtitles.SelectTrack(transcript, *track); err != nil {
+			return err
+		}
+	}
+	if *excludeSigns {
+		transcript = transcript.Without(subtitles.FlagSign | subtitles.FlagKaraoke)
+	}
+	transcript, err = normalizeTranscript(transcript, cfg)
 	if err != nil {
 		return err
 	}
+	parsedText := transcript.Text()
+	// 弹幕视图不输出说话人和分 P 标记，此时不能在提示词中要求模型使用它们
+	labelled := true
+
+	// 附带弹幕时按时间段交织字幕和弹幕，并在提示词中要求报告观众反应
+	if *danmakuPath != "" {
+		danmakuFile, err := os.Open(*danmakuPath)
+		if err != nil {
+			return fmt.Errorf("error reading danmaku file: %w", err)
+		}
+		items, err := subtitles.ParseDanmaku(danmakuFile)
+		danmakuFile.Close()
+		if err != nil {
+			return fmt.Errorf("error parsing danmaku file: %w", err)
+		}
+		parsedText = subtitles.RenderWithDanmaku(transcript, items, *danmakuSection, *danmakuTop)
+		labelled = false
+
+		danmakuCfg := *cfg
+		danmakuCfg.Prompt = cfg.Prompt + " " + cfg.DanmakuPrompt
+		cfg = &danmakuCfg
+	} else if cfg.Segment && !*rawText {
+		// 没有标点的 ASR 字幕按停顿断句分段，original.md 和提示词使用同一份文本
+		parsedText = normalize.FormatParagraphs(normalize.Segment(transcript, normalize.DefaultSegmentOptions), true)
+
+		segmentedCfg := *cfg
+		segmentedCfg.Prompt = cfg.SegmentedPrompt
+		cfg = &segmentedCfg
+	}
+
+	if labelled && len(transcript.Parts) > 0 {
+		partsCfg := *cfg
+		partsCfg.Prompt = cfg.Prompt + " " + cfg.PartsPrompt
+		cfg = &partsCfg
+	}
+	if labelled && len(transcript.Speakers()) > 0 {
+		speakersCfg := *cfg
+		speakersCfg.Prompt = cfg.Prompt + " " + cfg.SpeakersPrompt
+		cfg = &speakersCfg
+	}
+
+	// 有视频信息时附在提示词后面，帮助模型理解背景并纠正识别错误的专有名词
+	meta, err := loadMetadata(filePath)
+	if err != nil {
+		return err
+	}
+	if meta != nil {
+		metaCfg := *cfg
+		metaCfg.Prompt = cfg.Prompt + "\n\n" + meta.Prompt()
+		cfg = &metaCfg
+	}
 
 	// 执行字幕分析
 	ctx := context.Background()
@@ -65,10 +193,54 @@ func processSubtitles(filePath string, clientChoice string, cfg *config.Config)
 	}
 
 	// 保存分析结果
-	err = summarization.SaveSubtitleToFile(filePath, parsedText, result)
+	statsOpts := stats.DefaultOptions
+	statsOpts.MusicThreshold = cfg.MusicThreshold
+	saveOpts := summarization.SaveOptions{Chinese: cfg.ChineseOutput, Stats: stats.Compute(transcript, statsOpts), Metadata: meta}
+	if *zhOutputFlag != "" {
+		saveOpts.Chinese = *zhOutputFlag
+	}
+	err = summarization.SaveSubtitleToFileWithOptions(filePath, parsedText, result, saveOpts)
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// loadMetadata 读取 -meta 指定的视频信息，未指定时查找字幕文件旁边的元数据文件
+func loadMetadata(filePath string) (*metadata.VideoMetadata, error) {
+	if *metaFlag != "" {
+		return metadata.Load(*metaFlag)
+	}
+	return metadata.FindSidecar(filePath)
+}
+
+// normalizeTranscript 按 -normalize 或配置中的顺序清理字幕，并记录每道处理去掉的内容
+func normalizeTranscript(transcript *subtitles.Transcript, cfg *config.Config) (*subtitles.Transcript, error) {
+	names := cfg.Normalize
+	switch *normalizeFlag {
+	case "":
+	case "none":
+		names = nil
+	default:
+		names = strings.Split(*normalizeFlag, ",")
+	}
+
+	opts := normalize.Options{MusicPolicy: cfg.MusicPolicy, MusicThreshold: cfg.MusicThreshold, Chinese: cfg.Chinese}
+	if *musicPolicy != "" {
+		opts.MusicPolicy = *musicPolicy
+	}
+	if *zhFlag != "" {
+		opts.Chinese = *zhFlag
+	}
+
+	pipeline, err := normalize.NewPipeline(names, opts)
+	if err != nil {
+		return nil, err
+	}
+	transcript, reports := pipeline.Run(transcript)
+	for _, report := range reports {
+		log.Printf("Normalize %s", report)
+	}
+	return transcript, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runMerge 把多个分 P 的字幕按顺序合并为一个文件
+func runMerge(args []string) int {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	format := fs.String("to", "", "output format: srt, vtt, ass, bcc, json (legacy) or txt; inferred from the output extension when empty")
+	offsets := fs.String("offsets", "", "comma-separated start time of each part (e.g. 0,25:30,51:02 or 0,25m30s,51m2s); parts are placed back to back when empty")
+	gap := fs.Duration("gap", 0, "gap inserted between parts placed back to back")
+	encoding := fs.String("encoding", "", "character encoding of the input files; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s merge [flags] output part1 part2 ...\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 3 {
+		fs.Usage()
+		return 2
+	}
+
+	merged, err := mergeParts(fs.Args()[1:], *offsets, *gap, subtitles.ParseOptions{Encoding: *encoding})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "merge: %v\n", err)
+		return 1
+	}
+	if err := subtitles.WriteSubtitleFile(fs.Arg(0), *format, merged); err != nil {
+		fmt.Fprintf(os.Stderr, "merge: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// mergeParts 解析并合并分 P 字幕文件，分 P 标题取自文件名
+func mergeParts(files []string, offsets string, gap time.Duration, opts subtitles.ParseOptions) (*subtitles.Transcript, error) {
+	mergeOpts := subtitles.MergeOptions{Gap: gap}
+	if offsets != "" {
+		var err error
+		if mergeOpts.Offsets, err = parseOffsets(offsets); err != nil {
+			return nil, err
+		}
+	}
+
+	parts := make([]*subtitles.Transcript, 0, len(files))
+	for _, file := range files {
+		transcript, err := subtitles.ParseSubtitleFileWithOptions(file, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		parts = append(parts, transcript)
+		mergeOpts.Titles = append(mergeOpts.Titles, strings.TrimSuffix(filepath.Base(file), filepath.Ext(file)))
+	}
+	return subtitles.Merge(parts, mergeOpts)
+}
+
+// parseOffsets 解析以逗号分隔的时间列表，每项为 Go 时长（25m30s）或时钟格式（25:30、1:02:03）
+func parseOffsets(list string) ([]time.Duration, error) {
+	var offsets []time.Duration
+	for _, item := range strings.Split(list, ",") {
+		offset, err := parseOffset(strings.TrimSpace(item))
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+func parseOffset(s string) (time.Duration, error) {
+	if !strings.Contains(s, ":") {
+		return time.ParseDuration(s)
+	}
+
+	var total time.Duration
+	fields := strings.Split(s, ":")
+	if len(fields) > 3 {
+		return 0, fmt.Errorf("invalid offset %q", s)
+	}
+	for _, field := range fields {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil || value < 0 {
+			return 0, fmt.Errorf("invalid offset %q", s)
+		}
+		total = total*60 + time.Duration(value*float64(time.Second))
+	}
+	return total, nil
+}
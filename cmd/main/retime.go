@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// runRetime 平移、缩放或按对应点重新同步字幕时间，并以源格式写出
+func runRetime(args []string) int {
+	fs := flag.NewFlagSet("retime", flag.ExitOnError)
+	shift := fs.String("shift", "", "shift all cues by this amount, e.g. 1.5s, -800ms or -0:02.5")
+	scale := fs.Float64("scale", 0, "multiply all times by this factor")
+	fps := fs.String("fps", "", "fix frame rate drift as from:to, e.g. 23.976:25 for subtitles timed for 23.976 fps played at 25 fps")
+	anchors := fs.String("anchor", "", "resync by two anchor points as from=to,from=to, e.g. 0:01:00=0:01:02,1:20:00=1:20:05")
+	encoding := fs.String("encoding", "", "character encoding of the input file; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s retime [flags] input [output]\n\nThe output is written in the format of the input, to <input>.retimed<ext> when no output is given.\nScaling is applied before shifting; -anchor cannot be combined with the other operations.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() < 1 || fs.NArg() > 2 || (*shift == "" && *scale == 0 && *fps == "" && *anchors == "") {
+		fs.Usage()
+		return 2
+	}
+
+	input := fs.Arg(0)
+	output := fs.Arg(1)
+	if output == "" {
+		ext := filepath.Ext(input)
+		output = strings.TrimSuffix(input, ext) + ".retimed" + ext
+	}
+
+	scaleFactor, offset, err := retimeLinear(*shift, *scale, *fps, *anchors)
+	if err == nil {
+		err = subtitles.RetimeFile(input, output, scaleFactor, offset, subtitles.ParseOptions{Encoding: *encoding})
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "retime: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// retimeLinear 把缩放、平移或对应点参数合并为一个线性变换 t*scale+offset，缩放在平移之前
+func retimeLinear(shift string, scale float64, fps, anchors string) (float64, time.Duration, error) {
+	if anchors != "" {
+		if shift != "" || scale != 0 || fps != "" {
+			return 0, 0, fmt.Errorf("-anchor cannot be combined with -shift, -scale or -fps")
+		}
+		points, err := parseAnchors(anchors)
+		if err != nil {
+			return 0, 0, err
+		}
+		return subtitles.ResyncLinear(points[0], points[1])
+	}
+
+	factor := 1.0
+	if fps != "" {
+		from, to, ok := strings.Cut(fps, ":")
+		fromRate, err1 := strconv.ParseFloat(from, 64)
+		toRate, err2 := strconv.ParseFloat(to, 64)
+		if !ok || err1 != nil || err2 != nil {
+			return 0, 0, fmt.Errorf("invalid frame rates %q (expected from:to)", fps)
+		}
+		if fromRate <= 0 || toRate <= 0 {
+			return 0, 0, fmt.Errorf("invalid frame rates %g and %g", fromRate, toRate)
+		}
+		factor *= fromRate / toRate
+	}
+	if scale != 0 {
+		if scale < 0 {
+			return 0, 0, fmt.Errorf("invalid scale %g", scale)
+		}
+		factor *= scale
+	}
+	var offset time.Duration
+	if shift != "" {
+		negative := strings.HasPrefix(shift, "-")
+		var err error
+		if offset, err = parseOffset(strings.TrimPrefix(shift, "-")); err != nil {
+			return 0, 0, err
+		}
+		if negative {
+			offset = -offset
+		}
+	}
+	return factor, offset, nil
+}
+
+// parseAnchors 解析 "from=to,from=to" 形式的两个对应点
+func parseAnchors(list string) ([2]subtitles.Anchor, error) {
+	var points [2]subtitles.Anchor
+	items := strings.Split(list, ",")
+	if len(items) != 2 {
+		return points, fmt.Errorf("expected two anchor points, got %q", list)
+	}
+	for i, item := range items {
+		from, to, ok := strings.Cut(item, "=")
+		if !ok {
+			return points, fmt.Errorf("invalid anchor point %q (expected from=to)", item)
+		}
+		var err error
+		if points[i].From, err = parseOffset(strings.TrimSpace(from)); err != nil {
+			return points, err
+		}
+		if points[i].To, err = parseOffset(strings.TrimSpace(to)); err != nil {
+			return points, err
+		}
+	}
+	return points, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runSplit 把双语字幕拆成每种语言一个文件
+func runSplit(args []string) int {
+	fs := flag.NewFlagSet("split", flag.ExitOnError)
+	format := fs.String("to", "", "output format: srt, vtt, ass, bcc, json (legacy) or txt; same as the input when empty (srt for danmaku input)")
+	outDir := fs.String("o", "", "output directory; defaults to the directory of the input file")
+	encoding := fs.String("encoding", "", "character encoding of the input file; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s split [flags] input\n\nWrites one file per language, named <input>.<lang>.<ext>.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	input := fs.Arg(0)
+	transcript, err := subtitles.ParseSubtitleFileWithOptions(input, subtitles.ParseOptions{Encoding: *encoding})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "split: %v\n", err)
+		return 1
+	}
+	tracks := subtitles.SplitTracks(transcript)
+	if len(tracks) < 2 {
+		fmt.Fprintf(os.Stderr, "split: %s is not bilingual\n", input)
+		return 1
+	}
+
+	if *format == "" {
+		*format = transcript.Format
+		if _, err := subtitles.NewSubtitleWriter(*format); err != nil {
+			*format = subtitles.FormatSRT
+		}
+	}
+	dir := *outDir
+	if dir == "" {
+		dir = filepath.Dir(input)
+	}
+	base := strings.TrimSuffix(filepath.Base(input), filepath.Ext(input))
+
+	for _, track := range tracks {
+		output := filepath.Join(dir, base+"."+track.Lang+formatExt(*format))
+		if err := subtitles.WriteSubtitleFile(output, *format, track); err != nil {
+			fmt.Fprintf(os.Stderr, "split: %v\n", err)
+			return 1
+		}
+		fmt.Printf("%s: %d cues\n", output, len(track.Cues))
+	}
+	return 0
+}
+
+// formatExt 返回输出格式对应的扩展名
+func formatExt(format string) string {
+	switch strings.ToLower(format) {
+	case subtitles.FormatBCC, subtitles.FormatOldJSON:
+		return ".json"
+	case "ssa":
+		return ".ass"
+	}
+	return "." + strings.ToLower(format)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/stats"
+	"bilibili_subtitle/internal/subtitles"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runStats 输出字幕的统计数据，不调用模型
+func runStats(args []string) int {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print the statistics as JSON")
+	window := fs.Duration("window", stats.DefaultOptions.Window, "window used for the speech rate over time")
+	encoding := fs.String("encoding", "", "character encoding of the subtitle files; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s stats [flags] file...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	opts := stats.DefaultOptions
+	opts.Window = *window
+	exitCode := 0
+	for _, filePath := range fs.Args() {
+		transcript, err := subtitles.ParseSubtitleFileWithOptions(filePath, subtitles.ParseOptions{Encoding: *encoding})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filePath, err)
+			exitCode = 1
+			continue
+		}
+		// 与分析前的 speakers 处理一致，文本开头的 "姓名：" 也算作说话人
+		report := stats.Compute(subtitles.ExtractSpeakers(transcript), opts)
+
+		if *asJSON {
+			data, err := json.MarshalIndent(struct {
+				File string `json:"file"`
+				*stats.Report
+			}{filePath, report}, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", filePath, err)
+				return 1
+			}
+			fmt.Println(string(data))
+		} else {
+			fmt.Printf("# %s\n\n%s\n", filePath, report.Markdown())
+		}
+	}
+	return exitCode
+}
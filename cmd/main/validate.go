@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"flag"
+	"fmt"
+	"os"
+)
+
+// runValidate 解析字幕文件并输出解析器发现的问题。
+// 退出码：0 没有问题，1 存在警告，2 文件无法解析
+func runValidate(args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	encoding := fs.String("encoding", "", "character encoding of the subtitle files; detected automatically when empty")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s validate [flags] file...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	exitCode := 0
+	for _, filePath := range fs.Args() {
+		cues := 0
+		transcript, err := subtitles.StreamSubtitleFile(filePath, subtitles.ParseOptions{Encoding: *encoding}, func(subtitles.Cue) error {
+			cues++
+			return nil
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", filePath, err)
+			exitCode = 2
+			continue
+		}
+
+		for _, warning := range transcript.Warnings {
+			fmt.Printf("%s:%d: %s\n", filePath, warning.Line, warning.Message)
+		}
+		fmt.Printf("%s: %s, %d cues, %d warnings\n", filePath, transcript.Format, cues, len(transcript.Warnings))
+		if len(transcript.Warnings) > 0 && exitCode == 0 {
+			exitCode = 1
+		}
+	}
+	return exitCode
+}
@@ -8,6 +8,7 @@ require (
 	github.com/sashabaranov/go-openai v1.35.6
 	github.com/sqweek/dialog v0.0.0-20240226140203-065105509627
 	golang.org/x/sync v0.7.0
+	golang.org/x/text v0.16.0
 	google.golang.org/api v0.184.0
 )
 
@@ -39,7 +40,6 @@ require (
 	golang.org/x/net v0.27.0 // indirect
 	golang.org/x/oauth2 v0.21.0 // indirect
 	golang.org/x/sys v0.22.0 // indirect
-	golang.org/x/text v0.16.0 // indirect
 	golang.org/x/time v0.5.0 // indirect
 	google.golang.org/genproto v0.0.0-20240604185151-ef581f913117 // indirect
 	google.golang.org/genproto/googleapis/api v0.0.0-20240528184218-531527333157 // indirect
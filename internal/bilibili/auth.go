@@ -0,0 +1,115 @@
+package bilibili
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrSessionExpired 表示配置的登录凭据（SESSDATA）已过期或无效
+var ErrSessionExpired = errors.New("bilibili session expired or invalid; export fresh cookies or update SESSDATA")
+
+// secretCookies 是需要在日志中隐藏的 Cookie
+var secretCookies = []string{"SESSDATA", "bili_jct"}
+
+// LoadCookieFile 读取浏览器导出的 Netscape 格式 cookies.txt，只保留 bilibili.com 的 Cookie。
+// 文件中没有 SESSDATA 或 SESSDATA 已过期时返回错误
+func LoadCookieFile(path string) ([]*http.Cookie, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading cookie file: %w", err)
+	}
+	defer file.Close()
+
+	var cookies []*http.Cookie
+	var sessdata *http.Cookie
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		// 值为空的 Cookie 以制表符结尾，只能去掉换行符，不能去掉首尾空白
+		line := strings.TrimRight(scanner.Text(), "\r\n")
+		// curl 和 yt-dlp 用 "#HttpOnly_" 前缀标记 HttpOnly 的 Cookie，其他以 "#" 开头的行是注释
+		line = strings.TrimPrefix(line, "#HttpOnly_")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// domain, include subdomains, path, secure, expires, name, value
+		fields := strings.SplitN(line, "\t", 7)
+		if len(fields) != 7 {
+			return nil, fmt.Errorf("%s:%d: expected 7 tab-separated fields, got %d", path, lineNumber, len(fields))
+		}
+		domain := strings.TrimPrefix(fields[0], ".")
+		if domain != "bilibili.com" && !strings.HasSuffix(domain, ".bilibili.com") {
+			continue
+		}
+		expires, err := strconv.ParseInt(fields[4], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid expiry %q", path, lineNumber, fields[4])
+		}
+		cookie := &http.Cookie{Name: fields[5], Value: fields[6], Domain: fields[0], Path: fields[2]}
+		if expires > 0 {
+			cookie.Expires = time.Unix(expires, 0)
+		}
+		if cookie.Name == "SESSDATA" {
+			sessdata = cookie
+		}
+		cookies = append(cookies, cookie)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading cookie file: %w", err)
+	}
+
+	if sessdata == nil {
+		return nil, fmt.Errorf("no SESSDATA cookie for bilibili.com in %s", path)
+	}
+	if !sessdata.Expires.IsZero() && sessdata.Expires.Before(time.Now()) {
+		return nil, fmt.Errorf("SESSDATA in %s expired on %s: %w", path, sessdata.Expires.Format("2006-01-02"), ErrSessionExpired)
+	}
+	return cookies, nil
+}
+
+// SetCookies 设置请求 B 站 API 时附带的 Cookie，替换之前的设置
+func (c *Client) SetCookies(cookies []*http.Cookie) {
+	c.cookies = cookies
+}
+
+// Authenticated 判断客户端是否带有登录凭据
+func (c *Client) Authenticated() bool {
+	for _, cookie := range c.cookies {
+		if cookie.Name == "SESSDATA" && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Secrets 返回需要在日志中隐藏的 Cookie 值，包括 URL 解码后的形式
+func (c *Client) Secrets() []string {
+	var secrets []string
+	for _, cookie := range c.cookies {
+		for _, name := range secretCookies {
+			if cookie.Name != name || cookie.Value == "" {
+				continue
+			}
+			secrets = append(secrets, cookie.Value)
+			if decoded, err := url.QueryUnescape(cookie.Value); err == nil && decoded != cookie.Value {
+				secrets = append(secrets, decoded)
+			}
+		}
+	}
+	return secrets
+}
+
+// sendsCookies 判断请求是否发往 B 站 API，只有这些请求附带 Cookie，字幕文件所在的 CDN 不需要
+func (c *Client) sendsCookies(u *url.URL) bool {
+	if base, err := url.Parse(c.BaseURL); err == nil && u.Host == base.Host {
+		return true
+	}
+	host := u.Hostname()
+	return host == "bilibili.com" || strings.HasSuffix(host, ".bilibili.com")
+}
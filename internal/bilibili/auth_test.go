@@ -0,0 +1,100 @@
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/config"
+	"bilibili_subtitle/internal/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeCookieFile writes a Netscape cookies.txt with a SESSDATA cookie expiring at the given Unix time.
+func writeCookieFile(t *testing.T, expires int64) string {
+	t.Helper()
+	content := "# Netscape HTTP Cookie File\n" +
+		"\n" +
+		fmt.Sprintf("#HttpOnly_.bilibili.com\tTRUE\t/\tTRUE\t%d\tSESSDATA\tabc%%2C123%%2Cxyz*\n", expires) +
+		".bilibili.com\tTRUE\t/\tFALSE\t0\tbili_jct\tdef456\r\n" +
+		".bilibili.com\tTRUE\t/\tFALSE\t0\tbuvid_fp\t\n" +
+		".example.com\tTRUE\t/\tFALSE\t0\tSESSDATA\tother\n"
+	path := filepath.Join(t.TempDir(), "cookies.txt")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadCookieFile tests reading HttpOnly and empty-valued cookies, skipping other domains and rejecting expired sessions.
+func TestLoadCookieFile(t *testing.T) {
+	cookies, err := LoadCookieFile(writeCookieFile(t, 4102444800))
+	if err != nil {
+		t.Fatalf("LoadCookieFile returned error: %v", err)
+	}
+	if len(cookies) != 3 || cookies[0].Name != "SESSDATA" || cookies[0].Value != "abc%2C123%2Cxyz*" || cookies[1].Value != "def456" ||
+		cookies[2].Name != "buvid_fp" || cookies[2].Value != "" {
+		t.Errorf("cookies = %v", cookies)
+	}
+
+	if _, err := LoadCookieFile(writeCookieFile(t, 1)); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expired SESSDATA: got %v, want ErrSessionExpired", err)
+	}
+}
+
+// TestSessionExpired tests that cookies are sent to the API and that a rejected session is reported.
+func TestSessionExpired(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/x/web-interface/view", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("SESSDATA"); err != nil || cookie.Value != "stale" {
+			t.Errorf("SESSDATA cookie = %v, %v", cookie, err)
+		}
+		fmt.Fprint(w, `{"code":-101,"message":"账号未登录","data":null}`)
+	})
+	mux.HandleFunc("/x/player/v2", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"0","data":{"login_mid":0,"need_login_subtitle":true,"subtitle":{"subtitles":[]}}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5, SESSDATA: "stale"})
+	if _, err := client.Video(context.Background(), bvid.Ref{BVID: "BV1xx411c7mD"}); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("view: got %v, want ErrSessionExpired", err)
+	}
+	video := &Video{BVID: "BV1xx411c7mD"}
+	if _, err := client.Subtitles(context.Background(), video, Page{CID: 1, Page: 1}); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("player: got %v, want ErrSessionExpired", err)
+	}
+
+	// Without credentials the same responses mean that the subtitles need a login.
+	anonymous := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+	_, err := anonymous.Subtitles(context.Background(), video, Page{CID: 1, Page: 1})
+	if !errors.Is(err, ErrNoSubtitles) || !strings.Contains(err.Error(), "log in") {
+		t.Errorf("anonymous player: got %v", err)
+	}
+}
+
+// TestSecretsRedacted tests that both forms of the cookie values are hidden from logs.
+func TestSecretsRedacted(t *testing.T) {
+	cookies, err := LoadCookieFile(writeCookieFile(t, 0))
+	if err != nil {
+		t.Fatalf("LoadCookieFile returned error: %v", err)
+	}
+	client := NewClient(config.BilibiliConfig{})
+	client.SetCookies(cookies)
+	if !client.Authenticated() {
+		t.Fatal("client with SESSDATA is not authenticated")
+	}
+
+	var out strings.Builder
+	w := utils.NewRedactWriter(&out, client.Secrets()...)
+	fmt.Fprintln(w, "Cookie: SESSDATA=abc%2C123%2Cxyz*; bili_jct=def456 decoded=abc,123,xyz*")
+	if got := out.String(); strings.Contains(got, "abc") || strings.Contains(got, "def456") {
+		t.Errorf("secrets leaked: %s", got)
+	}
+}
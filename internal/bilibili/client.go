@@ -0,0 +1,133 @@
+// Package bilibili 访问 B 站的 API：查询视频信息，列出并下载播放器中的字幕，列出收藏夹、合集和投稿。
+// 配置登录凭据后可以取得需要登录的 AI 字幕。视频引用的解析见 bvid 包
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/config"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL 是 B 站 API 的地址
+const DefaultBaseURL = "https://api.bilibili.com"
+
+// codeNotLoggedIn 是需要登录的接口在未登录时返回的错误码
+const codeNotLoggedIn = -101
+
+// userAgent 是请求时使用的浏览器标识，B 站会拒绝部分非浏览器的请求
+const userAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+
+// Client 是 B 站 API 客户端
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+
+	cookies     []*http.Cookie // 登录凭据，见 SetCookies
+	wbiMu       sync.Mutex
+	wbiMixinKey string // 缓存的 WBI 签名密钥，见 wbiKey
+}
+
+// APIError 是 B 站 API 返回的非零错误码
+type APIError struct {
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("bilibili API error %d: %s", e.Code, e.Message)
+}
+
+// NewClient 根据配置创建客户端，BaseURL 为空时使用 DefaultBaseURL。
+// 配置中的 SESSDATA 和 bili_jct 会作为 Cookie 附带在请求中；cookies.txt 需要另外用 LoadCookieFile 读取
+func NewClient(cfg config.BilibiliConfig) *Client {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	client := &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: time.Duration(cfg.Timeout) * time.Second},
+	}
+	if cfg.SESSDATA != "" {
+		client.cookies = append(client.cookies, &http.Cookie{Name: "SESSDATA", Value: cfg.SESSDATA})
+	}
+	if cfg.BiliJCT != "" {
+		client.cookies = append(client.cookies, &http.Cookie{Name: "bili_jct", Value: cfg.BiliJCT})
+	}
+	return client
+}
+
+// getJSON 请求 BaseURL 下的 API，检查返回的错误码并把 data 字段解码到 v
+func (c *Client) getJSON(ctx context.Context, path string, query url.Values, v interface{}) error {
+	resp, err := c.get(ctx, c.BaseURL+path+"?"+query.Encode())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Code    int             `json:"code"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("error decoding response from %s: %w", path, err)
+	}
+	if envelope.Code == codeNotLoggedIn && c.Authenticated() {
+		// 带着 Cookie 仍然提示未登录，说明凭据已经失效
+		return fmt.Errorf("%w (%v)", ErrSessionExpired, &APIError{Code: envelope.Code, Message: envelope.Message})
+	}
+	if envelope.Code != 0 {
+		return &APIError{Code: envelope.Code, Message: envelope.Message}
+	}
+	if err := json.Unmarshal(envelope.Data, v); err != nil {
+		return fmt.Errorf("error decoding data from %s: %w", path, err)
+	}
+	return nil
+}
+
+// get 发送 GET 请求，状态码不是 200 时返回错误；调用方负责关闭 Body
+func (c *Client) get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Referer", "https://www.bilibili.com/")
+	if c.sendsCookies(req.URL) {
+		for _, cookie := range c.cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s from %s", resp.Status, req.URL.Path)
+	}
+	return resp, nil
+}
+
+// resolve 把 API 返回的地址（通常是 "//i0.hdslb.com/..." 这样省略协议的形式）转换为完整地址
+func (c *Client) resolve(ref string) (string, error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return base.ResolveReference(u).String(), nil
+}
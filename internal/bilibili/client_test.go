@@ -0,0 +1,160 @@
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestServer serves the view, player and tag APIs for BV1xx411c7mD (two parts) and one BCC subtitle file.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/x/web-interface/view", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("bvid") != "BV1xx411c7mD" {
+			fmt.Fprint(w, `{"code":-404,"message":"啥都木有","data":null}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"message":"0","data":{"bvid":"BV1xx411c7mD","aid":2,"title":"字幕君交流场所",
+			"owner":{"mid":2,"name":"碧诗"},"duration":600,"pubdate":1245384000,
+			"pages":[{"cid":62131,"page":1,"part":"上","duration":300},{"cid":62132,"page":2,"part":"下","duration":300}]}}`)
+	})
+	mux.HandleFunc("/x/player/v2", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cid") != "62132" {
+			fmt.Fprint(w, `{"code":0,"message":"0","data":{"subtitle":{"subtitles":[]}}}`)
+			return
+		}
+		host := strings.TrimPrefix(server.URL, "http:")
+		fmt.Fprintf(w, `{"code":0,"message":"0","data":{"subtitle":{"subtitles":[
+			{"id":1,"lan":"ai-zh","lan_doc":"中文（自动生成）","subtitle_url":"%[1]s/bfs/ai.json","type":1},
+			{"id":2,"lan":"zh-CN","lan_doc":"中文（中国）","subtitle_url":"%[1]s/bfs/zh.json","type":0},
+			{"id":3,"lan":"en-US","lan_doc":"English","subtitle_url":"%[1]s/bfs/en.json","type":0}]}}}`, host)
+	})
+	mux.HandleFunc("/x/tag/archive/tags", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":0,"message":"0","data":[{"tag_id":1,"tag_name":"字幕"},{"tag_id":2,"tag_name":"测试"}]}`)
+	})
+	mux.HandleFunc("/bfs/zh.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"font_size":0.4,"body":[{"from":0.5,"to":2,"sid":1,"location":2,"content":"大家好"},{"from":2,"to":3.25,"sid":2,"location":2,"content":"欢迎"}]}`)
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestFetchTranscript tests resolving the cid, picking the human Chinese track and parsing the BCC body.
+func TestFetchTranscript(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	ref := bvid.Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 2}
+	transcript, video, track, err := client.FetchTranscript(context.Background(), ref, "")
+	if err != nil {
+		t.Fatalf("FetchTranscript returned error: %v", err)
+	}
+	if video.Title != "字幕君交流场所" || video.Owner.Name != "碧诗" || len(video.Pages) != 2 {
+		t.Errorf("video = %+v", video)
+	}
+	if track.Lang != "zh-CN" || track.AI() {
+		t.Errorf("track = %+v, want the human zh-CN track", track)
+	}
+	if transcript.Format != "bcc" || transcript.Lang != "zh-CN" || len(transcript.Cues) != 2 {
+		t.Fatalf("transcript = %+v", transcript)
+	}
+	if cue := transcript.Cues[1]; cue.Text != "欢迎" || cue.Start != 2*time.Second || cue.End != 3250*time.Millisecond {
+		t.Errorf("cue = %+v", cue)
+	}
+
+	// The first part has no subtitles.
+	ref.Page = 1
+	if _, _, _, err := client.FetchTranscript(context.Background(), ref, ""); !errors.Is(err, ErrNoSubtitles) {
+		t.Errorf("P1: got %v, want ErrNoSubtitles", err)
+	}
+}
+
+// TestVideoMetadata tests fetching tags and building the metadata of a part.
+func TestVideoMetadata(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	ref := bvid.Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 2}
+	video, err := client.Video(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("Video returned error: %v", err)
+	}
+	tags, err := client.Tags(context.Background(), ref)
+	if err != nil {
+		t.Fatalf("Tags returned error: %v", err)
+	}
+	page, err := video.Page(2)
+	if err != nil {
+		t.Fatalf("Page returned error: %v", err)
+	}
+	meta := video.Metadata(page, tags)
+	if meta.Title != "字幕君交流场所" || meta.Part != "P2 下" || meta.Uploader != "碧诗" || meta.Duration != 300 {
+		t.Errorf("meta = %+v", meta)
+	}
+	if strings.Join(meta.Tags, ",") != "字幕,测试" {
+		t.Errorf("tags = %q", meta.Tags)
+	}
+	if meta.UploadDate != "2009-06-19" {
+		t.Errorf("upload date = %q, want 2009-06-19", meta.UploadDate)
+	}
+	if meta.URL != "https://www.bilibili.com/video/BV1xx411c7mD?p=2" {
+		t.Errorf("URL = %q", meta.URL)
+	}
+}
+
+// TestClientErrors tests API error codes and missing parts.
+func TestClientErrors(t *testing.T) {
+	server := newTestServer(t)
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	_, err := client.Video(context.Background(), bvid.Ref{BVID: "BV1zz411c7mD"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != -404 {
+		t.Errorf("got %v, want API error -404", err)
+	}
+
+	video, err := client.Video(context.Background(), bvid.Ref{BVID: "BV1xx411c7mD", AID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := video.Page(3); err == nil {
+		t.Error("expected error for missing part")
+	}
+}
+
+// TestSelectSubtitle tests language matching and the preference for human subtitles.
+func TestSelectSubtitle(t *testing.T) {
+	tracks := []SubtitleTrack{
+		{Lang: "ai-zh", Type: 1},
+		{Lang: "en-US"},
+		{Lang: "ai-en", Type: 1},
+	}
+	tests := []struct {
+		lang string
+		want string
+	}{
+		{"", "ai-zh"},
+		{"en", "en-US"},
+		{"zh", "ai-zh"},
+		{"ai-en", "en-US"},
+	}
+	for _, tt := range tests {
+		got, err := SelectSubtitle(tracks, tt.lang)
+		if err != nil || got.Lang != tt.want {
+			t.Errorf("%q: got %q (%v), want %q", tt.lang, got.Lang, err, tt.want)
+		}
+	}
+	if _, err := SelectSubtitle(tracks, "ja"); err == nil {
+		t.Error("expected error for missing language")
+	}
+}
@@ -0,0 +1,148 @@
+package bilibili
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ListItem 是收藏夹、合集、系列或 UP 主投稿列表中的一个视频
+type ListItem struct {
+	BVID  string `json:"bvid"`
+	AID   int64  `json:"aid"`
+	Title string `json:"title"`
+}
+
+// listPage 是列表接口的一页结果，Last 表示没有下一页
+type listPage struct {
+	Items []ListItem
+	Last  bool
+}
+
+// collect 从第 1 页开始逐页调用 fetch，直到最后一页或取到 limit 个视频（0 表示不限制）
+func collect(limit int, fetch func(pn int) (listPage, error)) ([]ListItem, error) {
+	var items []ListItem
+	for pn := 1; ; pn++ {
+		page, err := fetch(pn)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if limit > 0 && len(items) >= limit {
+			return items[:limit], nil
+		}
+		if page.Last {
+			return items, nil
+		}
+	}
+}
+
+// lastPage 根据页码、每页数量和总数判断第 pn 页是否为最后一页，count 为本页的视频数
+func lastPage(pn, ps, total, count int) bool {
+	return count == 0 || pn*ps >= total
+}
+
+// FavoriteVideos 列出收藏夹中的视频，mediaID 为收藏夹 ID（链接中的 fid）。
+// 音频等非视频内容会被跳过；私密收藏夹需要登录
+func (c *Client) FavoriteVideos(ctx context.Context, mediaID int64, limit int) ([]ListItem, error) {
+	return collect(limit, func(pn int) (listPage, error) {
+		query := url.Values{}
+		query.Set("media_id", strconv.FormatInt(mediaID, 10))
+		query.Set("pn", strconv.Itoa(pn))
+		query.Set("ps", "20")
+		query.Set("platform", "web")
+		var data struct {
+			Medias []struct {
+				ListItem
+				ID   int64 `json:"id"`
+				Type int   `json:"type"` // 2 为视频
+			} `json:"medias"`
+			HasMore bool `json:"has_more"`
+		}
+		if err := c.getJSON(ctx, "/x/v3/fav/resource/list", query, &data); err != nil {
+			return listPage{}, fmt.Errorf("error listing favourites %d: %w", mediaID, err)
+		}
+		page := listPage{Last: !data.HasMore || len(data.Medias) == 0}
+		for _, media := range data.Medias {
+			if media.Type != 2 {
+				continue
+			}
+			item := media.ListItem
+			item.AID = media.ID
+			page.Items = append(page.Items, item)
+		}
+		return page, nil
+	})
+}
+
+// SeasonVideos 列出 UP 主 mid 的合集 seasonID 中的视频（链接 collectiondetail?sid= 或 lists/<id>?type=season）
+func (c *Client) SeasonVideos(ctx context.Context, mid, seasonID int64, limit int) ([]ListItem, error) {
+	const ps = 30
+	return collect(limit, func(pn int) (listPage, error) {
+		query := url.Values{}
+		query.Set("mid", strconv.FormatInt(mid, 10))
+		query.Set("season_id", strconv.FormatInt(seasonID, 10))
+		query.Set("page_num", strconv.Itoa(pn))
+		query.Set("page_size", strconv.Itoa(ps))
+		query.Set("sort_reverse", "false")
+		var data struct {
+			Archives []ListItem `json:"archives"`
+			Page     struct {
+				Total int `json:"total"`
+			} `json:"page"`
+		}
+		if err := c.getJSON(ctx, "/x/polymer/web-space/seasons_archives_list", query, &data); err != nil {
+			return listPage{}, fmt.Errorf("error listing season %d: %w", seasonID, err)
+		}
+		return listPage{Items: data.Archives, Last: lastPage(pn, ps, data.Page.Total, len(data.Archives))}, nil
+	})
+}
+
+// SeriesVideos 列出 UP 主 mid 的系列 seriesID 中的视频（链接 seriesdetail?sid= 或 lists/<id>?type=series），按发布时间从早到晚排列
+func (c *Client) SeriesVideos(ctx context.Context, mid, seriesID int64, limit int) ([]ListItem, error) {
+	const ps = 30
+	return collect(limit, func(pn int) (listPage, error) {
+		query := url.Values{}
+		query.Set("mid", strconv.FormatInt(mid, 10))
+		query.Set("series_id", strconv.FormatInt(seriesID, 10))
+		query.Set("pn", strconv.Itoa(pn))
+		query.Set("ps", strconv.Itoa(ps))
+		query.Set("sort", "asc")
+		query.Set("only_normal", "true")
+		var data struct {
+			Archives []ListItem `json:"archives"`
+			Page     struct {
+				Total int `json:"total"`
+			} `json:"page"`
+		}
+		if err := c.getJSON(ctx, "/x/series/archives", query, &data); err != nil {
+			return listPage{}, fmt.Errorf("error listing series %d: %w", seriesID, err)
+		}
+		return listPage{Items: data.Archives, Last: lastPage(pn, ps, data.Page.Total, len(data.Archives))}, nil
+	})
+}
+
+// UploaderVideos 列出 UP 主 mid 的投稿，从新到旧排列。该接口需要 WBI 签名
+func (c *Client) UploaderVideos(ctx context.Context, mid int64, limit int) ([]ListItem, error) {
+	const ps = 30
+	return collect(limit, func(pn int) (listPage, error) {
+		query := url.Values{}
+		query.Set("mid", strconv.FormatInt(mid, 10))
+		query.Set("pn", strconv.Itoa(pn))
+		query.Set("ps", strconv.Itoa(ps))
+		query.Set("order", "pubdate")
+		var data struct {
+			List struct {
+				VList []ListItem `json:"vlist"`
+			} `json:"list"`
+			Page struct {
+				Count int `json:"count"`
+			} `json:"page"`
+		}
+		if err := c.getWBIJSON(ctx, "/x/space/wbi/arc/search", query, &data); err != nil {
+			return listPage{}, fmt.Errorf("error listing videos of uploader %d: %w", mid, err)
+		}
+		return listPage{Items: data.List.VList, Last: lastPage(pn, ps, data.Page.Count, len(data.List.VList))}, nil
+	})
+}
@@ -0,0 +1,128 @@
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/config"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestFavoriteVideos tests paging through a favourites folder and skipping audio items.
+func TestFavoriteVideos(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/x/v3/fav/resource/list", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("pn") {
+		case "1":
+			fmt.Fprint(w, `{"code":0,"message":"0","data":{"medias":[
+				{"id":1,"bvid":"BV1xx411c7m1","title":"一","type":2},
+				{"id":2,"bvid":"","title":"音频","type":12}],"has_more":true}}`)
+		case "2":
+			fmt.Fprint(w, `{"code":0,"message":"0","data":{"medias":[
+				{"id":3,"bvid":"BV1xx411c7m3","title":"三","type":2}],"has_more":false}}`)
+		default:
+			t.Errorf("unexpected page %s", r.URL.Query().Get("pn"))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	items, err := client.FavoriteVideos(context.Background(), 42, 0)
+	if err != nil {
+		t.Fatalf("FavoriteVideos returned error: %v", err)
+	}
+	if len(items) != 2 || items[0].BVID != "BV1xx411c7m1" || items[0].AID != 1 || items[1].Title != "三" {
+		t.Errorf("items = %+v", items)
+	}
+}
+
+// TestSeasonVideosLimit tests that the total and the limit stop the paging.
+func TestSeasonVideosLimit(t *testing.T) {
+	var requests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/x/polymer/web-space/seasons_archives_list", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		pn, _ := strconv.Atoi(r.URL.Query().Get("page_num"))
+		fmt.Fprint(w, `{"code":0,"message":"0","data":{"archives":[`)
+		for i := 0; i < 30; i++ {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			n := (pn-1)*30 + i + 1
+			fmt.Fprintf(w, `{"aid":%d,"bvid":"BV%d","title":"第%d集"}`, n, n, n)
+		}
+		fmt.Fprint(w, `],"page":{"page_num":1,"page_size":30,"total":60}}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	items, err := client.SeasonVideos(context.Background(), 1, 2, 0)
+	if err != nil {
+		t.Fatalf("SeasonVideos returned error: %v", err)
+	}
+	if len(items) != 60 || requests != 2 || items[59].Title != "第60集" {
+		t.Errorf("got %d items in %d requests", len(items), requests)
+	}
+
+	requests = 0
+	items, err = client.SeasonVideos(context.Background(), 1, 2, 10)
+	if err != nil || len(items) != 10 || requests != 1 {
+		t.Errorf("limit 10: got %d items in %d requests, %v", len(items), requests, err)
+	}
+}
+
+// TestSignWBI tests the WBI signature against the example from the web player.
+func TestSignWBI(t *testing.T) {
+	key := mixinKey("7cd084941338484aae1ad9425b84077c", "4932caff0ff746eab6f01bf08b70ac45")
+	if key != "ea1db124af3c7062474693fa704f4ff8" {
+		t.Fatalf("mixinKey = %q", key)
+	}
+	query := url.Values{}
+	query.Set("foo", "114")
+	query.Set("bar", "514")
+	query.Set("zab", "1919810")
+	signWBI(query, key, time.Unix(1702204169, 0))
+	if got := query.Get("w_rid"); got != "8f6f2b5b3d485fe1886cec6a0be8c5d4" {
+		t.Errorf("w_rid = %q", got)
+	}
+}
+
+// TestUploaderVideos tests that the uploader list fetches the WBI keys once and signs every page.
+func TestUploaderVideos(t *testing.T) {
+	var navRequests int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/x/web-interface/nav", func(w http.ResponseWriter, r *http.Request) {
+		navRequests++
+		fmt.Fprint(w, `{"code":-101,"message":"账号未登录","data":{"isLogin":false,"wbi_img":{
+			"img_url":"https://i0.hdslb.com/bfs/wbi/7cd084941338484aae1ad9425b84077c.png",
+			"sub_url":"https://i0.hdslb.com/bfs/wbi/4932caff0ff746eab6f01bf08b70ac45.png"}}}`)
+	})
+	mux.HandleFunc("/x/space/wbi/arc/search", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("w_rid") == "" || query.Get("wts") == "" {
+			t.Errorf("request is not signed: %s", r.URL.RawQuery)
+		}
+		if query.Get("pn") == "1" {
+			fmt.Fprint(w, `{"code":0,"message":"0","data":{"list":{"vlist":[{"aid":1,"bvid":"BV1","title":"新"}]},"page":{"pn":1,"ps":1,"count":31}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"message":"0","data":{"list":{"vlist":[{"aid":2,"bvid":"BV2","title":"旧"}]},"page":{"pn":2,"ps":30,"count":31}}}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	client := NewClient(config.BilibiliConfig{BaseURL: server.URL, Timeout: 5})
+
+	items, err := client.UploaderVideos(context.Background(), 7, 0)
+	if err != nil {
+		t.Fatalf("UploaderVideos returned error: %v", err)
+	}
+	if len(items) != 2 || items[1].BVID != "BV2" || navRequests != 1 {
+		t.Errorf("got %+v with %d nav requests", items, navRequests)
+	}
+}
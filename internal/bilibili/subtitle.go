@@ -0,0 +1,155 @@
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/subtitles"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSubtitles 表示视频（分 P）没有可用的字幕
+var ErrNoSubtitles = errors.New("video has no subtitles")
+
+// SubtitleTrack 是播放器中的一条字幕轨道
+type SubtitleTrack struct {
+	ID       int64  `json:"id"`
+	Lang     string `json:"lan"`     // 如 "zh-CN"、"en-US"，AI 字幕为 "ai-zh" 等
+	LangName string `json:"lan_doc"` // 如 "中文（中国）"
+	URL      string `json:"subtitle_url"`
+	Type     int    `json:"type"` // 0 为人工字幕，1 为 AI 字幕
+}
+
+// AI 判断是否为 AI 生成的字幕
+func (t SubtitleTrack) AI() bool {
+	return t.Type == 1 || strings.HasPrefix(t.Lang, "ai-")
+}
+
+// Subtitles 通过播放器接口列出分 P 的字幕轨道。部分 AI 字幕需要登录后才会列出：
+// 未登录且没有字幕时返回提示登录的 ErrNoSubtitles，带着凭据却未登录时返回 ErrSessionExpired
+func (c *Client) Subtitles(ctx context.Context, video *Video, page Page) ([]SubtitleTrack, error) {
+	query := url.Values{}
+	query.Set("bvid", video.BVID)
+	query.Set("cid", strconv.FormatInt(page.CID, 10))
+	var player struct {
+		LoginMID          int64 `json:"login_mid"`
+		NeedLoginSubtitle bool  `json:"need_login_subtitle"`
+		Subtitle          struct {
+			Subtitles []SubtitleTrack `json:"subtitles"`
+		} `json:"subtitle"`
+	}
+	if err := c.getJSON(ctx, "/x/player/v2", query, &player); err != nil {
+		return nil, fmt.Errorf("error listing subtitles of %s P%d: %w", video.BVID, page.Page, err)
+	}
+	if c.Authenticated() && player.LoginMID == 0 {
+		return nil, fmt.Errorf("error listing subtitles of %s P%d: %w", video.BVID, page.Page, ErrSessionExpired)
+	}
+	if !c.Authenticated() && player.NeedLoginSubtitle && len(player.Subtitle.Subtitles) == 0 {
+		return nil, fmt.Errorf("%s P%d: %w (log in with SESSDATA or a cookie file to list AI subtitles)", video.BVID, page.Page, ErrNoSubtitles)
+	}
+	return player.Subtitle.Subtitles, nil
+}
+
+// FetchSubtitle 下载一条字幕轨道并用 BCC JSON 解析器解析
+func (c *Client) FetchSubtitle(ctx context.Context, track SubtitleTrack) (*subtitles.Transcript, error) {
+	if track.URL == "" {
+		return nil, fmt.Errorf("subtitle track %s has no URL", track.Lang)
+	}
+	subtitleURL, err := c.resolve(track.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subtitle URL %q: %w", track.URL, err)
+	}
+	resp, err := c.get(ctx, subtitleURL)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading subtitle %s: %w", track.Lang, err)
+	}
+	defer resp.Body.Close()
+
+	transcript, err := subtitles.ParseAll(&subtitles.NewJSONSubtitleParser{}, resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing subtitle %s: %w", track.Lang, err)
+	}
+	if transcript.Lang == "" {
+		transcript.Lang = track.Lang
+	}
+	return transcript, nil
+}
+
+// SelectSubtitle 选择语言为 lang 的字幕轨道（如 "zh"、"en-US"，AI 字幕的 "ai-" 前缀不参与比较），
+// 同一语言优先选择人工字幕。lang 为空时优先选择中文字幕。没有字幕时返回 ErrNoSubtitles
+func SelectSubtitle(tracks []SubtitleTrack, lang string) (SubtitleTrack, error) {
+	if len(tracks) == 0 {
+		return SubtitleTrack{}, ErrNoSubtitles
+	}
+	preferred := lang
+	if preferred == "" {
+		preferred = "zh"
+	}
+
+	best, bestScore := -1, 0
+	for i, track := range tracks {
+		score := 1
+		if matchesLang(track.Lang, preferred) {
+			score += 2
+		}
+		if !track.AI() {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if lang != "" && !matchesLang(tracks[best].Lang, lang) {
+		available := make([]string, len(tracks))
+		for i, track := range tracks {
+			available[i] = track.Lang
+		}
+		return SubtitleTrack{}, fmt.Errorf("no %q subtitles (available: %s)", lang, strings.Join(available, ", "))
+	}
+	return tracks[best], nil
+}
+
+// matchesLang 判断轨道语言是否属于 lang，"zh" 可以匹配 "zh-CN"、"zh-Hans" 和 "ai-zh"
+func matchesLang(trackLang, lang string) bool {
+	trackLang = strings.ToLower(strings.TrimPrefix(trackLang, "ai-"))
+	lang = strings.ToLower(strings.TrimPrefix(lang, "ai-"))
+	return trackLang == lang || strings.HasPrefix(trackLang, lang+"-")
+}
+
+// FetchTranscript 下载视频中指定分 P 的字幕，返回字幕、视频信息以及所用的字幕轨道
+func (c *Client) FetchTranscript(ctx context.Context, ref bvid.Ref, lang string) (*subtitles.Transcript, *Video, SubtitleTrack, error) {
+	video, err := c.Video(ctx, ref)
+	if err != nil {
+		return nil, nil, SubtitleTrack{}, err
+	}
+	page, err := video.Page(ref.Page)
+	if err != nil {
+		return nil, nil, SubtitleTrack{}, err
+	}
+	transcript, track, err := c.FetchPageTranscript(ctx, video, page, lang)
+	if err != nil {
+		return nil, nil, SubtitleTrack{}, err
+	}
+	return transcript, video, track, nil
+}
+
+// FetchPageTranscript 下载已查询到的视频中一个分 P 的字幕，返回字幕以及所用的字幕轨道。
+// 处理同一视频的多个分 P 时用它避免重复查询视频信息
+func (c *Client) FetchPageTranscript(ctx context.Context, video *Video, page Page, lang string) (*subtitles.Transcript, SubtitleTrack, error) {
+	tracks, err := c.Subtitles(ctx, video, page)
+	if err != nil {
+		return nil, SubtitleTrack{}, err
+	}
+	track, err := SelectSubtitle(tracks, lang)
+	if err != nil {
+		return nil, SubtitleTrack{}, fmt.Errorf("%s P%d: %w", video.BVID, page.Page, err)
+	}
+	transcript, err := c.FetchSubtitle(ctx, track)
+	if err != nil {
+		return nil, SubtitleTrack{}, err
+	}
+	return transcript, track, nil
+}
@@ -0,0 +1,101 @@
+package bilibili
+
+import (
+	"bilibili_subtitle/internal/bvid"
+	"bilibili_subtitle/internal/metadata"
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// beijingTime 是 B 站显示发布时间所用的时区
+var beijingTime = time.FixedZone("CST", 8*3600)
+
+// Video 是视频的基本信息
+type Video struct {
+	BVID        string `json:"bvid"`
+	AID         int64  `json:"aid"`
+	Title       string `json:"title"`
+	Description string `json:"desc"`
+	Category    string `json:"tname"`
+	PubDate     int64  `json:"pubdate"`  // 发布时间，Unix 时间戳
+	Duration    int    `json:"duration"` // 所有分 P 的总时长，秒
+	Owner       Owner  `json:"owner"`
+	Pages       []Page `json:"pages"`
+}
+
+// Owner 是视频的 UP 主
+type Owner struct {
+	MID  int64  `json:"mid"`
+	Name string `json:"name"`
+}
+
+// Page 是视频的一个分 P
+type Page struct {
+	CID      int64  `json:"cid"`
+	Page     int    `json:"page"`
+	Part     string `json:"part"`     // 分 P 标题
+	Duration int    `json:"duration"` // 秒
+}
+
+// Video 通过 view 接口查询视频信息
+func (c *Client) Video(ctx context.Context, ref bvid.Ref) (*Video, error) {
+	query := url.Values{}
+	query.Set("bvid", ref.BVID)
+	var video Video
+	if err := c.getJSON(ctx, "/x/web-interface/view", query, &video); err != nil {
+		return nil, fmt.Errorf("error fetching video %s: %w", ref.BVID, err)
+	}
+	return &video, nil
+}
+
+// Page 返回第 n 个分 P，n 为 0 时返回第一个
+func (v *Video) Page(n int) (Page, error) {
+	if n == 0 {
+		n = 1
+	}
+	for _, page := range v.Pages {
+		if page.Page == n {
+			return page, nil
+		}
+	}
+	return Page{}, fmt.Errorf("video %s has no part %d (%d parts)", v.BVID, n, len(v.Pages))
+}
+
+// Tags 查询视频的标签
+func (c *Client) Tags(ctx context.Context, ref bvid.Ref) ([]string, error) {
+	query := url.Values{}
+	query.Set("bvid", ref.BVID)
+	var tags []struct {
+		Name string `json:"tag_name"`
+	}
+	if err := c.getJSON(ctx, "/x/tag/archive/tags", query, &tags); err != nil {
+		return nil, fmt.Errorf("error fetching tags of %s: %w", ref.BVID, err)
+	}
+	names := make([]string, len(tags))
+	for i, tag := range tags {
+		names[i] = tag.Name
+	}
+	return names, nil
+}
+
+// Metadata 返回分 P 的元数据；只有一个分 P 时不填写分 P 标题，时长为该分 P 的时长
+func (v *Video) Metadata(page Page, tags []string) *metadata.VideoMetadata {
+	meta := &metadata.VideoMetadata{
+		ID:          v.BVID,
+		Title:       v.Title,
+		Uploader:    v.Owner.Name,
+		Description: v.Description,
+		Tags:        tags,
+		Duration:    float64(page.Duration),
+		URL:         bvid.Ref{BVID: v.BVID, AID: v.AID, Page: page.Page}.String(),
+	}
+	if len(v.Pages) > 1 {
+		meta.Part = fmt.Sprintf("P%d %s", page.Page, page.Part)
+	}
+	if v.PubDate > 0 {
+		meta.UploadDate = time.Unix(v.PubDate, 0).In(beijingTime).Format("2006-01-02")
+	}
+	return meta
+}
@@ -0,0 +1,69 @@
+// Package bvid 在 B 站的 BV 号和 AV 号之间互相转换，并把各种形式的视频引用
+// （BV 号、AV 号、视频链接、手机分享链接、b23.tv 短链接）解析为统一的 Ref
+package bvid
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BV 号与 AV 号互转的参数，与 B 站网页端的实现一致
+const (
+	xorCode  = 23442827791579
+	maskCode = 1<<51 - 1
+	maxAID   = 1 << 51
+	alphabet = "FcwAPNKTMug3GV5Lj7EJnHpWsx4tb8haYeviqBz6rkCy12mUSDQX9RdoZf"
+	bvLength = 12
+)
+
+// ErrInvalidBV 表示字符串不是合法的 BV 号
+var ErrInvalidBV = errors.New("invalid BV id")
+
+// AVToBV 把 AV 号转换为 BV 号，AV 号必须在 1 到 2^51-1 之间
+func AVToBV(aid int64) (string, error) {
+	if aid <= 0 || aid >= maxAID {
+		return "", fmt.Errorf("AV id %d out of range", aid)
+	}
+	bv := []byte("BV1000000000")
+	n := (maxAID | aid) ^ xorCode
+	for i := bvLength - 1; n > 0; i-- {
+		bv[i] = alphabet[n%58]
+		n /= 58
+	}
+	bv[3], bv[9] = bv[9], bv[3]
+	bv[4], bv[7] = bv[7], bv[4]
+	return string(bv), nil
+}
+
+// BVToAV 把 BV 号转换为 AV 号；BV 号区分大小写，只有开头的 "BV" 可以是小写
+func BVToAV(bvid string) (int64, error) {
+	if len(bvid) != bvLength || !strings.EqualFold(bvid[:2], "BV") || bvid[2] != '1' {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidBV, bvid)
+	}
+	bv := []byte(bvid)
+	bv[3], bv[9] = bv[9], bv[3]
+	bv[4], bv[7] = bv[7], bv[4]
+
+	var n int64
+	for _, c := range bv[3:] {
+		i := strings.IndexByte(alphabet, c)
+		if i < 0 {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidBV, bvid)
+		}
+		n = n*58 + int64(i)
+	}
+	aid := (n & maskCode) ^ xorCode
+	if aid <= 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidBV, bvid)
+	}
+	return aid, nil
+}
+
+// NormalizeBV 返回以大写 "BV" 开头的 BV 号，不合法时返回错误
+func NormalizeBV(bvid string) (string, error) {
+	if _, err := BVToAV(bvid); err != nil {
+		return "", err
+	}
+	return "BV" + bvid[2:], nil
+}
@@ -0,0 +1,115 @@
+package bvid
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestConvert tests BV/AV conversion in both directions, including the largest AV id.
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		aid  int64
+		bvid string
+	}{
+		{2, "BV1xx411c7mD"},
+		{170001, "BV17x411w7KC"},
+		{111298867365120, "BV1L9Uoa9EUx"},
+		{1<<51 - 1, "BV1aPPTfmvQq"},
+	}
+	for _, tt := range tests {
+		if got, err := AVToBV(tt.aid); err != nil || got != tt.bvid {
+			t.Errorf("AVToBV(%d) = %q, %v; want %q", tt.aid, got, err, tt.bvid)
+		}
+		if got, err := BVToAV(tt.bvid); err != nil || got != tt.aid {
+			t.Errorf("BVToAV(%q) = %d, %v; want %d", tt.bvid, got, err, tt.aid)
+		}
+	}
+
+	for _, bvid := range []string{"BV1xx411c7m", "BV2xx411c7mD", "BV1xx411c7m0", "BV1xx411c7mDD"} {
+		if _, err := BVToAV(bvid); !errors.Is(err, ErrInvalidBV) {
+			t.Errorf("BVToAV(%q): got %v, want ErrInvalidBV", bvid, err)
+		}
+	}
+	for _, aid := range []int64{0, -1, 1 << 51} {
+		if _, err := AVToBV(aid); err == nil {
+			t.Errorf("AVToBV(%d): expected error", aid)
+		}
+	}
+}
+
+// TestParse tests ids, URL shapes, page numbers, start times and share text.
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Ref
+	}{
+		{"BV1xx411c7mD", Ref{BVID: "BV1xx411c7mD", AID: 2}},
+		{"bv1xx411c7mD", Ref{BVID: "BV1xx411c7mD", AID: 2}},
+		{"av170001", Ref{BVID: "BV17x411w7KC", AID: 170001}},
+		{"AV170001", Ref{BVID: "BV17x411w7KC", AID: 170001}},
+		{"https://www.bilibili.com/video/BV1xx411c7mD?p=3&t=120", Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 3, Start: 2 * time.Minute}},
+		{"bilibili.com/video/av170001/?t=1m30.5s", Ref{BVID: "BV17x411w7KC", AID: 170001, Start: 90500 * time.Millisecond}},
+		{"https://m.bilibili.com/video/BV1xx411c7mD?p=2", Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 2}},
+		{"https://www.bilibili.com/festival/2024bnj?bvid=BV1xx411c7mD", Ref{BVID: "BV1xx411c7mD", AID: 2}},
+		{"https://www.bilibili.com/list/watchlater?oid=170001&start_progress=15000", Ref{BVID: "BV17x411w7KC", AID: 170001, Start: 15 * time.Second}},
+		{"【字幕君交流场所】 https://www.bilibili.com/video/BV1xx411c7mD/?share_source=copy_web", Ref{BVID: "BV1xx411c7mD", AID: 2}},
+		{"https://b23.tv/BV1xx411c7mD", Ref{BVID: "BV1xx411c7mD", AID: 2}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("Parse(%q) = %+v, %v; want %+v", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"https://www.bilibili.com/", "hello", "BV1xx411c7m0", "https://b23.tv/av1bZ2x"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): expected error", input)
+		}
+	}
+	if _, err := Parse("【标题】 https://b23.tv/aBcD123 "); !errors.Is(err, ErrShortLink) {
+		t.Errorf("short link: got %v, want ErrShortLink", err)
+	}
+
+	ref := Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 3, Start: 90 * time.Second}
+	if got, want := ref.String(), "https://www.bilibili.com/video/BV1xx411c7mD?p=3&t=90"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// redirectTransport answers every request with a redirect taken from a fixed table.
+type redirectTransport map[string]string
+
+func (rt redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	target, ok := rt[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+	}
+	header := http.Header{}
+	header.Set("Location", target)
+	return &http.Response{StatusCode: http.StatusFound, Status: "302 Found", Header: header, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+}
+
+// TestResolve tests that short links are expanded through the injected client, following chained redirects.
+func TestResolve(t *testing.T) {
+	client := &http.Client{Transport: redirectTransport{
+		"https://b23.tv/aBcD123": "https://b23.tv/xYz789",
+		"https://b23.tv/xYz789":  "https://m.bilibili.com/video/BV1xx411c7mD?p=2&share_source=copy",
+	}}
+
+	ref, err := Resolve(context.Background(), client, "【标题】 https://b23.tv/aBcD123")
+	if want := (Ref{BVID: "BV1xx411c7mD", AID: 2, Page: 2}); err != nil || ref != want {
+		t.Errorf("Resolve = %+v, %v; want %+v", ref, err, want)
+	}
+	if _, err := Resolve(context.Background(), client, "b23.tv/missing"); err == nil {
+		t.Error("expected error for a short link that does not redirect")
+	}
+	if ref, err := Resolve(context.Background(), nil, "av2"); err != nil || ref.BVID != "BV1xx411c7mD" {
+		t.Errorf("Resolve(av2) = %+v, %v", ref, err)
+	}
+}
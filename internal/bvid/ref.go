@@ -0,0 +1,207 @@
+package bvid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrShortLink 表示引用是 b23.tv 短链接，需要用 Resolve 展开
+var ErrShortLink = errors.New("b23.tv short link must be resolved")
+
+// maxRedirects 是展开短链接时最多跟随的跳转次数
+const maxRedirects = 5
+
+// Ref 是对一个视频（或其中某个分 P 的某个时间点）的引用；BVID 和 AID 总是同时给出
+type Ref struct {
+	BVID  string
+	AID   int64
+	Page  int           // 分 P 序号，从 1 开始；0 表示未指定
+	Start time.Duration // 链接中 t 参数指定的开始时间
+}
+
+var (
+	urlPattern  = regexp.MustCompile(`(?i)(?:https?://)?(?:[a-z0-9-]+\.)*(?:bilibili\.com|b23\.tv)/[^\s"'<>，。】]*`)
+	bvPattern   = regexp.MustCompile(`(?:^|[^0-9A-Za-z])([Bb][Vv]1[0-9A-Za-z]{9})(?:$|[^0-9A-Za-z])`)
+	avPattern   = regexp.MustCompile(`(?i)(?:^|[^0-9a-z])av(\d+)(?:$|[^0-9a-z])`)
+	clockOffset = regexp.MustCompile(`^(?:(\d+)h)?(?:(\d+)m)?(?:(\d+(?:\.\d+)?)s?)?$`)
+)
+
+// Parse 解析 BV 号、AV 号（"av170001"）、视频链接（可以带 p 和 t 参数，可以省略协议），
+// 以及包含这些内容的分享文本。b23.tv 短链接不发送网络请求，返回 ErrShortLink
+func Parse(s string) (Ref, error) {
+	s = strings.TrimSpace(s)
+	if link := urlPattern.FindString(s); link != "" {
+		return parseURL(link)
+	}
+	return parseID(s)
+}
+
+// Resolve 与 Parse 相同，但会通过 client 展开 b23.tv 短链接；client 为 nil 时使用 http.DefaultClient
+func Resolve(ctx context.Context, client *http.Client, s string) (Ref, error) {
+	ref, err := Parse(s)
+	if !errors.Is(err, ErrShortLink) {
+		return ref, err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	// 只读取 Location，不让 client 自动跟随跳转
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	link := withScheme(urlPattern.FindString(strings.TrimSpace(s)))
+	for i := 0; i < maxRedirects; i++ {
+		next, err := location(ctx, &noRedirect, link)
+		if err != nil {
+			return Ref{}, err
+		}
+		ref, err = parseURL(next)
+		if !errors.Is(err, ErrShortLink) {
+			return ref, err
+		}
+		link = next
+	}
+	return Ref{}, fmt.Errorf("too many redirects resolving %s", s)
+}
+
+// String 返回引用的规范链接
+func (r Ref) String() string {
+	query := url.Values{}
+	if r.Page > 1 {
+		query.Set("p", strconv.Itoa(r.Page))
+	}
+	if r.Start > 0 {
+		query.Set("t", strconv.FormatFloat(r.Start.Seconds(), 'f', -1, 64))
+	}
+	link := "https://www.bilibili.com/video/" + r.BVID
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	return link
+}
+
+// location 请求短链接并返回跳转的目标地址
+func location(ctx context.Context, client *http.Client, link string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", link, err)
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	resp.Body.Close()
+
+	if resp.StatusCode < 300 || resp.StatusCode >= 400 {
+		return "", fmt.Errorf("error resolving %s: unexpected status %s", link, resp.Status)
+	}
+	target, err := resp.Location()
+	if err != nil {
+		return "", fmt.Errorf("error resolving %s: %w", link, err)
+	}
+	return target.String(), nil
+}
+
+// parseURL 解析 bilibili.com 或 b23.tv 的链接
+func parseURL(link string) (Ref, error) {
+	u, err := url.Parse(withScheme(link))
+	if err != nil {
+		return Ref{}, fmt.Errorf("invalid video URL %q: %w", link, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "b23.tv" || strings.HasSuffix(host, ".b23.tv") {
+		// b23.tv/BV1xx411c7mD 这样直接带 BV 号的短链接不需要展开
+		if ref, err := parseID(strings.Trim(u.Path, "/")); err == nil {
+			return ref, nil
+		}
+		return Ref{}, ErrShortLink
+	}
+
+	query := u.Query()
+	ref, err := parseID(u.Path)
+	if err != nil {
+		// 活动页、稍后再看等页面把视频放在查询参数中
+		for _, key := range []string{"bvid", "aid", "oid"} {
+			if value := query.Get(key); value != "" {
+				if key != "bvid" {
+					value = "av" + value
+				}
+				if ref, err = parseID(value); err == nil {
+					break
+				}
+			}
+		}
+		if err != nil {
+			return Ref{}, fmt.Errorf("no BV or AV id in %q", link)
+		}
+	}
+
+	if p, err := strconv.Atoi(query.Get("p")); err == nil && p > 0 {
+		ref.Page = p
+	}
+	t := query.Get("t")
+	if t == "" {
+		t = query.Get("start_progress")
+		if ms, err := strconv.ParseInt(t, 10, 64); err == nil {
+			t = strconv.FormatFloat(float64(ms)/1000, 'f', -1, 64)
+		}
+	}
+	if start, ok := parseOffset(t); ok {
+		ref.Start = start
+	}
+	return ref, nil
+}
+
+// parseID 在文本中查找 BV 号或 AV 号并补全另一个
+func parseID(s string) (Ref, error) {
+	if m := bvPattern.FindStringSubmatch(s); m != nil {
+		bvid, err := NormalizeBV(m[1])
+		if err != nil {
+			return Ref{}, err
+		}
+		aid, _ := BVToAV(bvid)
+		return Ref{BVID: bvid, AID: aid}, nil
+	}
+	if m := avPattern.FindStringSubmatch(s); m != nil {
+		aid, err := strconv.ParseInt(m[1], 10, 64)
+		if err != nil {
+			return Ref{}, fmt.Errorf("invalid AV id %q", m[1])
+		}
+		bvid, err := AVToBV(aid)
+		if err != nil {
+			return Ref{}, err
+		}
+		return Ref{BVID: bvid, AID: aid}, nil
+	}
+	return Ref{}, fmt.Errorf("no BV or AV id in %q", s)
+}
+
+// parseOffset 解析 t 参数："120"、"120.5" 或 "1m30s"、"1h2m3s"
+func parseOffset(s string) (time.Duration, bool) {
+	m := clockOffset.FindStringSubmatch(s)
+	if s == "" || m == nil {
+		return 0, false
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.ParseFloat(m[3], 64)
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds*float64(time.Second))
+	return d.Round(time.Millisecond), true
+}
+
+// withScheme 为省略协议的链接补上 https://
+func withScheme(link string) string {
+	if strings.Contains(link, "://") {
+		return link
+	}
+	return "https://" + link
+}
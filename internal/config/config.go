@@ -1,6 +1,9 @@
 package config
 
 import (
+	"bilibili_subtitle/internal/normalize"
+	"bilibili_subtitle/internal/utils"
+	"fmt"
 	"log"
 	"os"
 )
@@ -11,7 +14,18 @@ type Config struct {
 	OpenaiAPIKey      string
 	GeminiModelConfig GeminiModelConfig
 	OpenaiModelConfig OpenaiModelConfig
+	BilibiliConfig    BilibiliConfig
 	Prompt            string
+	DanmakuPrompt     string   // Appended to Prompt when danmaku is analysed together with the subtitles
+	SegmentedPrompt   string   // Used instead of Prompt when the transcript is segmented into sentences and paragraphs
+	PartsPrompt       string   // Appended to the prompt when the subtitles of several parts are analysed together
+	SpeakersPrompt    string   // Appended to the prompt when the transcript has speaker labels
+	Segment           bool     // Merge cues into punctuated sentences and paragraphs instead of joining them with commas
+	Normalize         []string // Ordered normalisation passes applied to the transcript before analysis
+	MusicPolicy       string   // What the "music" pass does with lyrics: "tag", "drop" or "keep"
+	MusicThreshold    float64  // Cues whose BCC music probability reaches this value are treated as lyrics
+	Chinese           string   // Simplified/Traditional conversion applied to cue text by the "zh" pass (e.g. "t2s", "s2twp"); empty disables it
+	ChineseOutput     string   // Simplified/Traditional conversion applied to the generated analysis; empty disables it
 	Proxy             string
 }
 
@@ -36,6 +50,31 @@ type OpenaiModelConfig struct {
 	Endpoint    string  // OpenAPI server URL
 }
 
+// BilibiliConfig holds the configuration for the Bilibili API client.
+type BilibiliConfig struct {
+	BaseURL    string // Bilibili API server URL
+	Timeout    int    // Timeout for requests to the Bilibili API, in seconds
+	SESSDATA   string // Session cookie of a logged-in account, needed for AI subtitles and member-only videos
+	BiliJCT    string // CSRF cookie (bili_jct) of the same session
+	CookieFile string // Netscape cookies.txt exported from a logged-in browser; used instead of SESSDATA and BiliJCT when set
+}
+
+// String hides the API keys so that the configuration can be logged safely.
+func (c Config) String() string {
+	type plain Config
+	c.GeminiAPIKey = utils.Redact(c.GeminiAPIKey)
+	c.OpenaiAPIKey = utils.Redact(c.OpenaiAPIKey)
+	return fmt.Sprintf("%+v", plain(c))
+}
+
+// String hides the session cookies so that the configuration can be logged safely.
+func (c BilibiliConfig) String() string {
+	type plain BilibiliConfig
+	c.SESSDATA = utils.Redact(c.SESSDATA)
+	c.BiliJCT = utils.Redact(c.BiliJCT)
+	return fmt.Sprintf("%+v", plain(c))
+}
+
 func LoadConfigValue(key string) string {
 	val := os.Getenv(key)
 	if val == "" {
@@ -48,6 +87,10 @@ func NewConfig() *Config {
 	// Prompt is the text template to be used by the generative AI model.
 	//Prompt1 := "The following is the content of the subtitles. Speaking intervals are separated by commas, please provide a comprehensive analysis in Chinese:"
 	Prompt2 := "Here is a transcript of video subtitles, with speaking intervals separated by commas. Please conduct a thorough analysis of the themes, content, and any cultural nuances present in these subtitles. Summarize the key points and provide insights into the dialogue dynamics. All analysis and summary should be presented clearly in Chinese."
+	SegmentedPrompt := "Here is a transcript of video subtitles. Punctuation was restored automatically from speaking pauses, so sentence boundaries may be imprecise; each paragraph starts with its timestamp. Please conduct a thorough analysis of the themes, content, and any cultural nuances present in these subtitles. Summarize the key points and provide insights into the dialogue dynamics. All analysis and summary should be presented clearly in Chinese."
+	PartsPrompt := "The transcript covers several parts (P1, P2, ...) of one video, in order. Each part begins with a marker such as 【P2 title】. When referring to a moment, use the part and the time within that part, for example \"P3 12:30\"."
+	SpeakersPrompt := "The transcript is labelled with speakers: each speaker turn starts on a new line with the speaker's name followed by a colon. Attribute statements and opinions to the speakers by name, and describe how the conversation moves between them, including who leads, who asks and who answers."
+	DanmakuPrompt := "The input is divided into time sections. Each section lists the subtitles spoken in it, followed by the most frequent viewer danmaku (bullet comments) with their counts. In addition to the analysis above, report the audience reactions for each section, pointing out where viewers were most engaged, amused, confused or critical, all in Chinese."
 	return &Config{
 		GeminiAPIKey: LoadConfigValue("GEMINI_API_KEY"),
 		GeminiModelConfig: GeminiModelConfig{
@@ -68,7 +111,23 @@ func NewConfig() *Config {
 			Timeout:     30,                                 // Timeout in seconds
 			Endpoint:    LoadConfigValue("OPENAI_API_BASE"), // Default OpenAI endpoint
 		},
-		Prompt: Prompt2,
-		Proxy:  LoadConfigValue("HTTP_PROXY"),
+		BilibiliConfig: BilibiliConfig{
+			BaseURL: "https://api.bilibili.com",
+			Timeout: 30,
+			// Credentials are optional, so unset variables are not reported
+			SESSDATA:   os.Getenv("BILIBILI_SESSDATA"),
+			BiliJCT:    os.Getenv("BILIBILI_BILI_JCT"),
+			CookieFile: os.Getenv("BILIBILI_COOKIE_FILE"),
+		},
+		Prompt:          Prompt2,
+		DanmakuPrompt:   DanmakuPrompt,
+		SegmentedPrompt: SegmentedPrompt,
+		PartsPrompt:     PartsPrompt,
+		SpeakersPrompt:  SpeakersPrompt,
+		Segment:         true,
+		Normalize:       append([]string(nil), normalize.DefaultPasses...),
+		MusicPolicy:     normalize.DefaultOptions.MusicPolicy,
+		MusicThreshold:  normalize.DefaultOptions.MusicThreshold,
+		Proxy:           LoadConfigValue("HTTP_PROXY"),
 	}
 }
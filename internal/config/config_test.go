@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -62,3 +64,22 @@ func TestNewConfig(t *testing.T) {
 	os.Unsetenv("OPENAI_MODEL_NAME")
 	os.Unsetenv("OPENAI_API_BASE")
 }
+
+// TestConfigString tests that the API keys and session cookies are hidden when the configuration is printed.
+func TestConfigString(t *testing.T) {
+	cfg := Config{
+		GeminiAPIKey:   "gemini-secret",
+		OpenaiAPIKey:   "sk-openai-secret",
+		BilibiliConfig: BilibiliConfig{BaseURL: "https://api.bilibili.com", SESSDATA: "abc%2C123", BiliJCT: "def456"},
+	}
+	for _, out := range []string{fmt.Sprintf("%+v", cfg), fmt.Sprintf("%v", cfg), fmt.Sprint(&cfg)} {
+		for _, secret := range []string{"gemini-secret", "sk-openai-secret", "abc%2C123", "def456"} {
+			if strings.Contains(out, secret) {
+				t.Errorf("secret %q leaked: %s", secret, out)
+			}
+		}
+		if !strings.Contains(out, "SESSDATA:[REDACTED]") || !strings.Contains(out, "GeminiAPIKey:[REDACTED]") {
+			t.Errorf("missing redacted fields: %s", out)
+		}
+	}
+}
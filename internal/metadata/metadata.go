@@ -0,0 +1,160 @@
+// Package metadata 描述视频的标题、UP 主、简介、标签等信息，用于在提示词中提供背景，
+// 并作为 front matter 写入分析结果
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDescription 是写入提示词的简介的最大字数
+const maxDescription = 500
+
+// sidecarSuffixes 是字幕文件旁边的元数据文件后缀，.info.json 为 yt-dlp 的格式
+var sidecarSuffixes = []string{".meta.json", ".info.json"}
+
+// VideoMetadata 是视频的基本信息；JSON 字段与 yt-dlp 的 .info.json 兼容
+type VideoMetadata struct {
+	ID          string   `json:"id,omitempty"` // BV 号等视频标识
+	Title       string   `json:"title"`
+	Part        string   `json:"part,omitempty"` // 分 P 标题
+	Uploader    string   `json:"uploader,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Duration    float64  `json:"duration,omitempty"`    // 秒
+	UploadDate  string   `json:"upload_date,omitempty"` // "2024-03-01" 或 yt-dlp 的 "20240301"
+	URL         string   `json:"webpage_url,omitempty"`
+}
+
+// Load 读取 JSON 格式的元数据文件
+func Load(path string) (*VideoMetadata, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading metadata file: %w", err)
+	}
+	var meta VideoMetadata
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("error decoding metadata file %s: %w", path, err)
+	}
+	return &meta, nil
+}
+
+// FindSidecar 查找并读取字幕文件旁边的元数据文件（"视频.meta.json" 或 "视频.info.json"），
+// 字幕文件名中的语言后缀（"视频.zh-CN.srt"）会被忽略。没有元数据文件时返回 nil, nil
+func FindSidecar(subtitlePath string) (*VideoMetadata, error) {
+	base := strings.TrimSuffix(subtitlePath, filepath.Ext(subtitlePath))
+	for _, candidate := range []string{base, strings.TrimSuffix(base, filepath.Ext(base))} {
+		for _, suffix := range sidecarSuffixes {
+			path := candidate + suffix
+			if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return Load(path)
+		}
+	}
+	return nil, nil
+}
+
+// SidecarPath 返回保存字幕文件元数据时使用的路径
+func SidecarPath(subtitlePath string) string {
+	return strings.TrimSuffix(subtitlePath, filepath.Ext(subtitlePath)) + sidecarSuffixes[0]
+}
+
+// Save 把元数据写入 JSON 文件
+func (m *VideoMetadata) Save(path string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding metadata: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("error writing metadata file: %w", err)
+	}
+	return nil
+}
+
+// Prompt 把元数据渲染为追加在提示词后面的英文说明，简介过长时截断
+func (m *VideoMetadata) Prompt() string {
+	var out strings.Builder
+	out.WriteString("Video information (use it to ground the analysis; names and terms in the transcript may be misrecognised versions of those below):")
+	for _, field := range m.fields(maxDescription) {
+		fmt.Fprintf(&out, "\n%s: %s", field.label, field.value)
+	}
+	return out.String()
+}
+
+// FrontMatter 把元数据渲染为 YAML front matter，字符串值都加上引号
+func (m *VideoMetadata) FrontMatter() string {
+	var out strings.Builder
+	out.WriteString("---\n")
+	for _, field := range m.fields(0) {
+		if field.key == "tags" {
+			quoted := make([]string, len(m.Tags))
+			for i, tag := range m.Tags {
+				quoted[i] = strconv.Quote(tag)
+			}
+			fmt.Fprintf(&out, "tags: [%s]\n", strings.Join(quoted, ", "))
+			continue
+		}
+		fmt.Fprintf(&out, "%s: %s\n", field.key, strconv.Quote(field.value))
+	}
+	out.WriteString("---\n\n")
+	return out.String()
+}
+
+type field struct {
+	key   string // front matter 中的键
+	label string // 提示词中的名称
+	value string
+}
+
+// fields 返回非空的字段；maxDescription 大于 0 时截断简介
+func (m *VideoMetadata) fields(maxDescription int) []field {
+	var fields []field
+	add := func(key, label, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, field{key, label, value})
+		}
+	}
+	add("title", "Title", m.Title)
+	add("part", "Part", m.Part)
+	add("uploader", "Uploader", m.Uploader)
+	add("upload_date", "Upload date", formatDate(m.UploadDate))
+	if m.Duration > 0 {
+		add("duration", "Duration", formatDuration(m.Duration))
+	}
+	add("tags", "Tags", strings.Join(m.Tags, ", "))
+	add("id", "ID", m.ID)
+	add("url", "URL", m.URL)
+
+	description := strings.TrimSpace(m.Description)
+	if maxDescription > 0 && utf8.RuneCountInString(description) > maxDescription {
+		description = string([]rune(description)[:maxDescription]) + "…"
+	}
+	add("description", "Description", description)
+	return fields
+}
+
+// formatDate 把 yt-dlp 的 "20240301" 转换为 "2024-03-01"，其他写法原样返回
+func formatDate(date string) string {
+	if len(date) == 8 {
+		if _, err := strconv.Atoi(date); err == nil {
+			return date[:4] + "-" + date[4:6] + "-" + date[6:]
+		}
+	}
+	return date
+}
+
+// formatDuration 把秒数格式化为 "mm:ss"，超过一小时时为 "h:mm:ss"
+func formatDuration(seconds float64) string {
+	total := int(seconds + 0.5)
+	if total >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total/60%60, total%60)
+	}
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
@@ -0,0 +1,78 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFindSidecar tests finding .meta.json and yt-dlp .info.json files next to subtitle files.
+func TestFindSidecar(t *testing.T) {
+	dir := t.TempDir()
+	meta, err := FindSidecar(filepath.Join(dir, "video.srt"))
+	if err != nil || meta != nil {
+		t.Fatalf("no sidecar: got %+v, %v; want nil, nil", meta, err)
+	}
+
+	// yt-dlp names subtitles "video.zh-CN.srt" next to "video.info.json".
+	info := `{"id":"BV1xx411c7mD","title":"字幕君交流场所","uploader":"碧诗","upload_date":"20090619","duration":300.4,"tags":["字幕"],"extractor":"BiliBili"}`
+	if err := os.WriteFile(filepath.Join(dir, "video.info.json"), []byte(info), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err = FindSidecar(filepath.Join(dir, "video.zh-CN.srt"))
+	if err != nil {
+		t.Fatalf("FindSidecar returned error: %v", err)
+	}
+	if meta == nil || meta.Title != "字幕君交流场所" || meta.UploadDate != "20090619" || meta.Duration != 300.4 {
+		t.Fatalf("meta = %+v", meta)
+	}
+
+	// A saved .meta.json takes precedence over .info.json.
+	saved := &VideoMetadata{Title: "覆盖"}
+	if err := saved.Save(SidecarPath(filepath.Join(dir, "video.srt"))); err != nil {
+		t.Fatalf("Save returned error: %v", err)
+	}
+	meta, err = FindSidecar(filepath.Join(dir, "video.srt"))
+	if err != nil || meta == nil || meta.Title != "覆盖" {
+		t.Errorf("got %+v, %v; want the .meta.json file", meta, err)
+	}
+}
+
+// TestPrompt tests the rendered prompt and the truncated description.
+func TestPrompt(t *testing.T) {
+	meta := &VideoMetadata{
+		Title:       "字幕君交流场所",
+		Uploader:    "碧诗",
+		UploadDate:  "20090619",
+		Duration:    3725,
+		Tags:        []string{"字幕", "测试"},
+		Description: strings.Repeat("简", maxDescription+10),
+	}
+	prompt := meta.Prompt()
+	for _, want := range []string{"\nTitle: 字幕君交流场所", "\nUploader: 碧诗", "\nUpload date: 2009-06-19", "\nDuration: 1:02:05", "\nTags: 字幕, 测试"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt does not contain %q:\n%s", want, prompt)
+		}
+	}
+	if strings.Contains(prompt, "Part:") || strings.Contains(prompt, "URL:") {
+		t.Errorf("prompt contains empty fields:\n%s", prompt)
+	}
+	if !strings.HasSuffix(prompt, strings.Repeat("简", maxDescription)+"…") {
+		t.Errorf("description is not truncated to %d characters", maxDescription)
+	}
+}
+
+// TestFrontMatter tests the YAML front matter written to analysis.md.
+func TestFrontMatter(t *testing.T) {
+	meta := &VideoMetadata{ID: "BV1xx411c7mD", Title: `说 "你好"`, Duration: 65, Tags: []string{"字幕", "测试"}}
+	want := "---\n" +
+		"title: \"说 \\\"你好\\\"\"\n" +
+		"duration: \"01:05\"\n" +
+		"tags: [\"字幕\", \"测试\"]\n" +
+		"id: \"BV1xx411c7mD\"\n" +
+		"---\n\n"
+	if got := meta.FrontMatter(); got != want {
+		t.Errorf("FrontMatter() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"bilibili_subtitle/internal/zhconv"
+)
+
+// ChinesePass 对字幕文本做简繁转换，Converter 为 nil 时不做处理
+type ChinesePass struct {
+	Converter *zhconv.Converter
+}
+
+func (ChinesePass) Name() string { return "zh" }
+
+// Apply 转换每条字幕的文本
+func (p ChinesePass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	if p.Converter == nil {
+		return transcript
+	}
+	return mapCues(transcript, p.Converter.Convert)
+}
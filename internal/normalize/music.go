@@ -0,0 +1,64 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"fmt"
+)
+
+// 音乐字幕的处理方式
+const (
+	MusicTag  = "tag"  // 标记为歌词并在文本前加上标签
+	MusicDrop = "drop" // 删除歌词
+	MusicKeep = "keep" // 不做处理
+)
+
+// 加在文本前面的标签，让模型能区分歌词、屏幕文字和对白
+const (
+	lyricsLabel   = "[歌词] "
+	onScreenLabel = "[屏幕文字] "
+)
+
+// MusicPass 根据 BCC JSON 的 music 和 location 字段处理 B 站 AI 字幕中的歌词和屏幕文字：
+// music 不低于 Threshold 的条目按 Policy 标记或删除，不在默认位置的条目标记为屏幕文字。
+// 标记同时设置 FlagKaraoke / FlagSign，因此 -exclude-signs 也能去掉它们
+type MusicPass struct {
+	Policy    string
+	Threshold float64
+}
+
+func (MusicPass) Name() string { return "music" }
+
+// Apply 按策略处理歌词和屏幕文字
+func (p MusicPass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	if p.Policy == MusicKeep {
+		return transcript
+	}
+
+	result := *transcript
+	result.Cues = make([]subtitles.Cue, 0, len(transcript.Cues))
+	for _, cue := range transcript.Cues {
+		if cue.Music > 0 && cue.Music >= p.Threshold {
+			if p.Policy == MusicDrop {
+				continue
+			}
+			if !cue.Has(subtitles.FlagKaraoke) {
+				cue.Flags |= subtitles.FlagKaraoke
+				cue.Text = lyricsLabel + cue.Text
+			}
+		} else if cue.Positional() && !cue.Has(subtitles.FlagSign) {
+			cue.Flags |= subtitles.FlagSign
+			cue.Text = onScreenLabel + cue.Text
+		}
+		result.Cues = append(result.Cues, cue)
+	}
+	return &result
+}
+
+// validateMusicPolicy 检查音乐字幕的处理方式是否有效
+func validateMusicPolicy(policy string) error {
+	switch policy {
+	case MusicTag, MusicDrop, MusicKeep:
+		return nil
+	}
+	return fmt.Errorf("unknown music policy %q (available: %s, %s, %s)", policy, MusicTag, MusicDrop, MusicKeep)
+}
@@ -0,0 +1,178 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"bilibili_subtitle/internal/zhconv"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pass 是一道规范化处理，输入的 Transcript 不会被修改
+type Pass interface {
+	Name() string
+	Apply(transcript *subtitles.Transcript) *subtitles.Transcript
+}
+
+// Report 记录一道处理去掉的字符数和估算的 token 数
+type Report struct {
+	Pass   string
+	Chars  int
+	Tokens int
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%s: removed %d chars (~%d tokens)", r.Pass, r.Chars, r.Tokens)
+}
+
+// DefaultPasses 是默认的处理顺序
+var DefaultPasses = []string{"music", "zh", "tags", "speakers", "dedupe", "filler", "whitespace"}
+
+// Options 是各道处理的参数
+type Options struct {
+	MusicPolicy    string  // 歌词的处理方式，见 Music* 常量
+	MusicThreshold float64 // music 字段不低于该值时视为歌词
+	Chinese        string  // 简繁转换配置（见 zhconv 包），为空时不转换
+}
+
+// DefaultOptions 是默认参数
+var DefaultOptions = Options{
+	MusicPolicy:    MusicTag,
+	MusicThreshold: 0.5,
+}
+
+// passes 按名称登记所有可用的处理
+var passes = map[string]func(opts Options) (Pass, error){
+	"music": func(opts Options) (Pass, error) {
+		if err := validateMusicPolicy(opts.MusicPolicy); err != nil {
+			return nil, err
+		}
+		return MusicPass{Policy: opts.MusicPolicy, Threshold: opts.MusicThreshold}, nil
+	},
+	"zh": func(opts Options) (Pass, error) {
+		if opts.Chinese == "" {
+			return ChinesePass{}, nil
+		}
+		converter, err := zhconv.New(opts.Chinese)
+		if err != nil {
+			return nil, err
+		}
+		return ChinesePass{Converter: converter}, nil
+	},
+	"tags":       func(Options) (Pass, error) { return TagPass{}, nil },
+	"speakers":   func(Options) (Pass, error) { return SpeakerPass{}, nil },
+	"dedupe":     func(Options) (Pass, error) { return DedupePass{}, nil },
+	"filler":     func(Options) (Pass, error) { return FillerPass{Words: DefaultFillers}, nil },
+	"whitespace": func(Options) (Pass, error) { return WhitespacePass{}, nil },
+}
+
+// Names 返回所有可用处理的名称
+func Names() []string {
+	names := make([]string, 0, len(passes))
+	for name := range passes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Pipeline 按顺序执行一组处理
+type Pipeline struct {
+	Passes []Pass
+}
+
+// NewPipeline 根据名称列表和参数创建处理链，名称未知或参数无效时返回错误
+func NewPipeline(names []string, opts Options) (*Pipeline, error) {
+	pipeline := &Pipeline{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		factory, ok := passes[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown normalisation pass %q (available: %s)", name, strings.Join(Names(), ", "))
+		}
+		pass, err := factory(opts)
+		if err != nil {
+			return nil, err
+		}
+		pipeline.Passes = append(pipeline.Passes, pass)
+	}
+	return pipeline, nil
+}
+
+// Run 依次执行所有处理，返回处理后的 Transcript 以及每道处理的统计
+func (p *Pipeline) Run(transcript *subtitles.Transcript) (*subtitles.Transcript, []Report) {
+	reports := make([]Report, 0, len(p.Passes))
+	text := transcript.Text()
+	for _, pass := range p.Passes {
+		transcript = pass.Apply(transcript)
+		next := transcript.Text()
+		reports = append(reports, Report{
+			Pass:   pass.Name(),
+			Chars:  utf8.RuneCountInString(text) - utf8.RuneCountInString(next),
+			Tokens: EstimateTokens(text) - EstimateTokens(next),
+		})
+		text = next
+	}
+	return transcript, reports
+}
+
+// EstimateTokens 粗略估算文本的 token 数：每个 CJK 字符、标点符号各算一个，
+// 连续的字母数字按每四个字符一个计算，空白不计
+func EstimateTokens(text string) int {
+	tokens, word := 0, 0
+	flush := func() {
+		tokens += (word + 3) / 4
+		word = 0
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsSpace(r):
+			flush()
+		case isCJK(r):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word++
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// isCJK 判断是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+// mapCues 对每条字幕的文本执行 fn，去掉处理后文本为空的条目
+func mapCues(transcript *subtitles.Transcript, fn func(text string) string) *subtitles.Transcript {
+	result := *transcript
+	result.Cues = make([]subtitles.Cue, 0, len(transcript.Cues))
+	for _, cue := range transcript.Cues {
+		cue.Text = fn(cue.Text)
+		if cue.Text != "" {
+			result.Cues = append(result.Cues, cue)
+		}
+	}
+	return &result
+}
+
+// mapLines 对每一行执行 fn，并去掉处理后为空的行
+func mapLines(text string, fn func(line string) string) string {
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(fn(line)); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,157 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func transcriptOf(texts ...string) *subtitles.Transcript {
+	transcript := &subtitles.Transcript{}
+	for i, text := range texts {
+		transcript.Cues = append(transcript.Cues, subtitles.Cue{
+			Index: i + 1,
+			Start: time.Duration(i) * time.Second,
+			End:   time.Duration(i+1) * time.Second,
+			Text:  text,
+		})
+	}
+	return transcript
+}
+
+func texts(transcript *subtitles.Transcript) []string {
+	var result []string
+	for _, cue := range transcript.Cues {
+		result = append(result, cue.Text)
+	}
+	return result
+}
+
+// TestPasses tests each pass on typical auto-generated caption noise.
+func TestPasses(t *testing.T) {
+	tests := []struct {
+		pass Pass
+		in   []string
+		want []string
+	}{
+		{TagPass{}, []string{"<i>你好</i>", `{\an8}<font color="#fff">标题</font>`, "[音乐]", "（笑）真的吗 ♪"},
+			[]string{"你好", "标题", "真的吗"}},
+		{DedupePass{}, []string{"今天", "今天我们", "今天我们来讲", "今天我们来讲\n字幕格式", "字幕格式", "下一句"},
+			[]string{"今天我们来讲", "字幕格式", "下一句"}},
+		{FillerPass{Words: DefaultFillers}, []string{"嗯，我觉得，那个，挺好的", "那个人很好啊", "嗯嗯", "我们走吧，啊！"},
+			[]string{"我觉得，挺好的", "那个人很好啊", "我们走吧！"}},
+		{WhitespacePass{}, []string{"  你好   世界 ", "ＡＢＣ１２３　测试", "真的吗?好的,走吧", "1,000 apples"},
+			[]string{"你好世界", "ABC123 测试", "真的吗？好的，走吧", "1,000 apples"}},
+	}
+	for _, tt := range tests {
+		got := texts(tt.pass.Apply(transcriptOf(tt.in...)))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.pass.Name(), got, tt.want)
+		}
+	}
+}
+
+// TestDedupeExtendsTiming tests that merged duplicates extend the end time of the kept cue.
+func TestDedupeExtendsTiming(t *testing.T) {
+	result := DedupePass{}.Apply(transcriptOf("一样", "一样", "一样"))
+	if len(result.Cues) != 1 || result.Cues[0].End != 3*time.Second {
+		t.Errorf("cues = %+v", result.Cues)
+	}
+}
+
+// TestDedupeKeepsDialogueAfterComment tests that a dialogue line is not merged into an identical comment before it.
+func TestDedupeKeepsDialogueAfterComment(t *testing.T) {
+	transcript := transcriptOf("大家好", "大家好")
+	transcript.Cues[0].Flags = subtitles.FlagComment
+
+	result := DedupePass{}.Apply(transcript)
+	if len(result.Cues) != 2 || result.Cues[1].Has(subtitles.FlagComment) || result.Cues[1].Text != "大家好" {
+		t.Errorf("cues = %+v", result.Cues)
+	}
+	if text := result.Text(); text != "大家好, " {
+		t.Errorf("Text() = %q", text)
+	}
+}
+
+// TestPipelineReports tests pass ordering, unknown names and per-pass statistics.
+func TestPipelineReports(t *testing.T) {
+	if _, err := NewPipeline([]string{"tags", "nope"}, DefaultOptions); err == nil {
+		t.Error("expected error for unknown pass")
+	}
+
+	pipeline, err := NewPipeline(DefaultPasses, DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := transcriptOf("<i>嗯，大家好</i>", "嗯，大家好", "[音乐]")
+	result, reports := pipeline.Run(original)
+
+	if got := texts(result); !reflect.DeepEqual(got, []string{"大家好"}) {
+		t.Errorf("texts = %q", got)
+	}
+	if len(original.Cues) != 3 || original.Cues[0].Text != "<i>嗯，大家好</i>" {
+		t.Error("Run modified its input")
+	}
+
+	want := []Report{
+		{Pass: "music", Chars: 0, Tokens: 0},
+		{Pass: "zh", Chars: 0, Tokens: 0},
+		{Pass: "tags", Chars: 13, Tokens: 12},
+		{Pass: "speakers", Chars: 0, Tokens: 0},
+		{Pass: "dedupe", Chars: 7, Tokens: 6},
+		{Pass: "filler", Chars: 2, Tokens: 2},
+		{Pass: "whitespace", Chars: 0, Tokens: 0},
+	}
+	if !reflect.DeepEqual(reports, want) {
+		t.Errorf("reports = %+v, want %+v", reports, want)
+	}
+}
+
+// TestMusicPass tests the music policies and on-screen labelling of BCC cues.
+func TestMusicPass(t *testing.T) {
+	original := transcriptOf("我们开始吧", "啦啦啦", "第一章", "轻声哼唱")
+	original.Cues[1].Music = 0.9
+	original.Cues[2].Location = 8
+	original.Cues[3].Music = 0.3
+
+	tagged := MusicPass{Policy: MusicTag, Threshold: 0.5}.Apply(original)
+	if got, want := texts(tagged), []string{"我们开始吧", "[歌词] 啦啦啦", "[屏幕文字] 第一章", "轻声哼唱"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("tag: got %q, want %q", got, want)
+	}
+	if !tagged.Cues[1].Has(subtitles.FlagKaraoke) || !tagged.Cues[2].Has(subtitles.FlagSign) {
+		t.Errorf("tag: flags not set: %+v", tagged.Cues)
+	}
+
+	dropped := MusicPass{Policy: MusicDrop, Threshold: 0.5}.Apply(original)
+	if got, want := texts(dropped), []string{"我们开始吧", "[屏幕文字] 第一章", "轻声哼唱"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("drop: got %q, want %q", got, want)
+	}
+
+	if _, err := NewPipeline([]string{"music"}, Options{MusicPolicy: "mute"}); err == nil {
+		t.Error("expected error for unknown music policy")
+	}
+}
+
+// TestChinesePass tests that the zh pass converts cue text only when a conversion is configured.
+func TestChinesePass(t *testing.T) {
+	original := transcriptOf("這個軟體的影片", "頭髮乾了")
+
+	pipeline, err := NewPipeline([]string{"zh"}, Options{Chinese: "tw2sp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	converted, _ := pipeline.Run(original)
+	if got, want := texts(converted), []string{"这个软件的视频", "头发干了"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	pipeline, _ = NewPipeline([]string{"zh"}, Options{})
+	if unchanged, _ := pipeline.Run(original); !reflect.DeepEqual(texts(unchanged), texts(original)) {
+		t.Errorf("zh without a conversion changed the text: %q", texts(unchanged))
+	}
+
+	if _, err := NewPipeline([]string{"zh"}, Options{Chinese: "s2x"}); err == nil {
+		t.Error("expected error for unknown conversion")
+	}
+}
@@ -0,0 +1,235 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TagPass 去掉 HTML 标签、ASS 覆盖标签以及 [音乐]、（笑）之类的声音标注
+type TagPass struct{}
+
+// SpeakerPass 把文本开头的 "姓名：" 标签移到条目的说话人字段，见 subtitles.ExtractSpeakers
+type SpeakerPass struct{}
+
+// DedupePass 合并连续重复的字幕，包括自动字幕中逐渐变长的滚动字幕
+type DedupePass struct{}
+
+// FillerPass 去掉单独成句的语气词和口头禅
+type FillerPass struct {
+	Words []string
+}
+
+// WhitespacePass 统一空白、全角字母数字以及中文语境中的标点
+type WhitespacePass struct{}
+
+// DefaultFillers 是默认去掉的语气词和口头禅
+var DefaultFillers = []string{"嗯", "啊", "呃", "额", "唔", "那个", "就是说", "然后呢"}
+
+var (
+	htmlTag    = regexp.MustCompile(`</?[a-zA-Z][^<>]*>`)
+	assTag     = regexp.MustCompile(`\{\\[^{}]*\}`)
+	soundLabel = regexp.MustCompile(`(?i)[\[【(（]\s*(?:音乐|背景音乐|音效|笑|笑声|大笑|掌声|鼓掌|欢呼|叹气|咳嗽|bgm|music|laughter|laughs|applause|cheering)\s*[\]】)）]|[♪♫♬]+`)
+)
+
+func (TagPass) Name() string { return "tags" }
+
+// Apply 去掉标签和声音标注，只剩下标注的条目会被删除
+func (TagPass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	return mapCues(transcript, func(text string) string {
+		text = htmlTag.ReplaceAllString(text, "")
+		text = assTag.ReplaceAllString(text, "")
+		text = soundLabel.ReplaceAllString(text, "")
+		return mapLines(text, func(line string) string { return line })
+	})
+}
+
+func (SpeakerPass) Name() string { return "speakers" }
+
+// Apply 分离出说话人标签
+func (SpeakerPass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	return subtitles.ExtractSpeakers(transcript)
+}
+
+func (DedupePass) Name() string { return "dedupe" }
+
+// Apply 去掉与上一条字幕重复的行；整条重复或只是上一条的延长时，
+// 合并到上一条并延长其结束时间。不同说话人的条目不会合并，注释行前后的条目也不会合并，
+// 否则与注释内容相同的对白会被并入注释而不再输出
+func (DedupePass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	result := *transcript
+	result.Cues = make([]subtitles.Cue, 0, len(transcript.Cues))
+	for _, cue := range transcript.Cues {
+		if len(result.Cues) == 0 || cue.Has(subtitles.FlagComment) {
+			result.Cues = append(result.Cues, cue)
+			continue
+		}
+		last := &result.Cues[len(result.Cues)-1]
+		if last.Has(subtitles.FlagComment) || cue.Speaker != last.Speaker {
+			result.Cues = append(result.Cues, cue)
+			continue
+		}
+		lastLines := strings.Split(last.Text, "\n")
+
+		var lines []string
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if !containsLine(lastLines, line) {
+				lines = append(lines, line)
+			}
+		}
+
+		tail := lastLines[len(lastLines)-1]
+		switch {
+		case len(lines) == 0:
+			// 完全重复
+		case len(lines) == 1 && strings.HasPrefix(lines[0], tail):
+			// 滚动字幕：这一条是上一条最后一行的延长
+			lastLines[len(lastLines)-1] = lines[0]
+			last.Text = strings.Join(lastLines, "\n")
+		default:
+			cue.Text = strings.Join(lines, "\n")
+			result.Cues = append(result.Cues, cue)
+			continue
+		}
+		if cue.End > last.End {
+			last.End = cue.End
+		}
+	}
+	return &result
+}
+
+// containsLine 判断 lines 中是否有与 line 相同的行
+func containsLine(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
+
+func (FillerPass) Name() string { return "filler" }
+
+// Apply 去掉前后都是标点、空白或行首行尾的语气词（可以重复，如“嗯嗯”），
+// 因此“那个人”“好啊”中的字不会被误删
+func (p FillerPass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	return mapCues(transcript, func(text string) string {
+		return mapLines(text, func(line string) string { return p.removeFillers(line) })
+	})
+}
+
+// removeFillers 把一行按标点和空白切分为片段，去掉只由语气词组成的片段及其后的停顿标点
+func (p FillerPass) removeFillers(line string) string {
+	var out strings.Builder
+	runes := []rune(line)
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && !isPause(runes[end]) && !isTerminal(runes[end]) {
+			end++
+		}
+		delim := end
+		for delim < len(runes) && (isPause(runes[delim]) || isTerminal(runes[delim])) {
+			delim++
+		}
+
+		segment, delimiters := string(runes[start:end]), string(runes[end:delim])
+		if segment != "" && p.isFiller(segment) {
+			// 去掉语气词；如果它后面是句末标点，让前一句以该标点结束
+			if terminal := strings.TrimFunc(delimiters, isPause); terminal != "" && out.Len() > 0 {
+				trimmed := strings.TrimRightFunc(out.String(), isPause)
+				out.Reset()
+				out.WriteString(trimmed + terminal)
+			}
+		} else if segment != "" || out.Len() > 0 {
+			out.WriteString(segment + delimiters)
+		}
+		start = delim
+	}
+	return out.String()
+}
+
+// isFiller 判断片段是否只由语气词组成
+func (p FillerPass) isFiller(segment string) bool {
+	for segment != "" {
+		matched := false
+		for _, word := range p.Words {
+			if word != "" && strings.HasPrefix(segment, word) {
+				segment = segment[len(word):]
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// isPause 判断是否为句中停顿的标点或空白
+func isPause(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("，,、；;：:…～~", r)
+}
+
+// isTerminal 判断是否为句末标点
+func isTerminal(r rune) bool {
+	return strings.ContainsRune("。！？!?.", r)
+}
+
+func (WhitespacePass) Name() string { return "whitespace" }
+
+// halfToFullPunct 是中文语境中应使用的全角标点
+var halfToFullPunct = map[rune]rune{',': '，', '!': '！', '?': '？', ';': '；', ':': '：'}
+
+// Apply 把全角字母、数字和空格转换为半角，合并连续空白，去掉中文字符之间的空格，
+// 并把紧跟在中文后面的半角标点转换为全角
+func (WhitespacePass) Apply(transcript *subtitles.Transcript) *subtitles.Transcript {
+	return mapCues(transcript, func(text string) string {
+		return mapLines(text, unifyLine)
+	})
+}
+
+// unifyLine 处理一行文本，见 WhitespacePass.Apply
+func unifyLine(line string) string {
+	var runes []rune
+	for _, r := range line {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '０' && r <= '９', r >= 'Ａ' && r <= 'Ｚ', r >= 'ａ' && r <= 'ｚ':
+			r = r - '０' + '0'
+		}
+		if unicode.IsSpace(r) {
+			if len(runes) == 0 || runes[len(runes)-1] == ' ' {
+				continue
+			}
+			r = ' '
+		}
+		runes = append(runes, r)
+	}
+
+	out := runes[:0]
+	for i, r := range runes {
+		var prev, next rune
+		if len(out) > 0 {
+			prev = out[len(out)-1]
+		}
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		if r == ' ' && (isCJKOrPunct(prev) && isCJKOrPunct(next)) {
+			continue
+		}
+		if full, ok := halfToFullPunct[r]; ok && isCJK(prev) {
+			r = full
+		}
+		out = append(out, r)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// isCJKOrPunct 判断是否为中日韩文字或全角标点
+func isCJKOrPunct(r rune) bool {
+	return isCJK(r) || (r >= 0x3000 && r <= 0x303F) || (r >= 0xFF00 && r <= 0xFFEF)
+}
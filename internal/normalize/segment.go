@@ -0,0 +1,283 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// SegmentOptions 是断句和分段的参数
+type SegmentOptions struct {
+	SentencePause  time.Duration // 条目之间的停顿不短于该值时断句
+	ParagraphPause time.Duration // 条目之间的停顿不短于该值时分段
+	MaxSentence    int           // 句子达到该字数后，在下一个条目边界处断句
+	MaxParagraph   int           // 段落中已完成的句子达到该字数后，在下一个条目边界处分段
+}
+
+// DefaultSegmentOptions 是适合 B 站 AI 字幕的默认参数
+var DefaultSegmentOptions = SegmentOptions{
+	SentencePause:  800 * time.Millisecond,
+	ParagraphPause: 2 * time.Second,
+	MaxSentence:    50,
+	MaxParagraph:   300,
+}
+
+// Paragraph 是断句分段后的一段文本
+type Paragraph struct {
+	Start   time.Duration // 第一条字幕的开始时间；合并的分 P 字幕中为相对于所属分 P 的时间
+	Timed   bool          // 第一条字幕是否有时间信息
+	Part    int           // 所属分 P 的序号，0 表示字幕不是由多个分 P 合并而来
+	Speaker string        // 说话人，未标注时为空
+	Text    string
+}
+
+// questionWords 出现在以 "呢" 结尾的句子中时，句子按问句处理
+var questionWords = []string{"什么", "怎么", "为什么", "哪", "谁", "几", "多少", "是不是", "有没有"}
+
+// segmenter 保存断句分段的状态
+type segmenter struct {
+	opts       SegmentOptions
+	paragraphs []Paragraph
+
+	current  strings.Builder // 当前段落
+	start    time.Duration
+	timed    bool
+	part     subtitles.Part  // 当前所在的分 P
+	speaker  string          // 当前段落的说话人
+	sentence strings.Builder // 当前句子中尚未结束的部分
+	labelled bool            // 当前段落由歌词、屏幕文字等带标签的条目组成
+}
+
+// Segment 把没有标点的 ASR 字幕合并成句子和段落：停顿较长或句子过长时断句，
+// 停顿更长或段落过长时分段，同一句中的条目之间补上逗号，句末补上句号或问号。
+// 源文件已有的标点会保留；歌词和屏幕文字单独成行，不与对白合并。注释行会被跳过。
+// 合并的分 P 字幕在每个分 P 开始处另起一段，并插入一个 "【P3 标题】" 形式的标记段落；
+// 说话人改变时另起一段，没有标注说话人的条目沿用上一位说话人
+func Segment(transcript *subtitles.Transcript, opts SegmentOptions) []Paragraph {
+	s := &segmenter{opts: opts}
+	var prev *subtitles.Cue
+	for i := range transcript.Cues {
+		cue := &transcript.Cues[i]
+		if cue.Has(subtitles.FlagComment) {
+			continue
+		}
+		text := joinLines(cue.Text)
+		if text == "" {
+			continue
+		}
+
+		if part, ok := transcript.PartOf(*cue); ok && part.Index != s.part.Index {
+			s.endParagraph()
+			s.part = part
+			s.paragraphs = append(s.paragraphs, Paragraph{Part: part.Index, Text: "【" + part.Label() + "】"})
+			prev = nil
+		}
+		if cue.Speaker != "" && cue.Speaker != s.speaker {
+			s.endParagraph()
+			s.speaker = cue.Speaker
+			prev = nil
+		}
+
+		labelled := cue.Has(subtitles.FlagKaraoke | subtitles.FlagSign)
+		switch {
+		case prev == nil:
+		case labelled || s.labelled:
+			// 带标签的条目每条一行，与对白之间另起一段
+			if labelled && s.labelled {
+				s.current.WriteString("\n" + text)
+				prev = cue
+				continue
+			}
+			s.endParagraph()
+		default:
+			s.boundary(s.gap(prev, cue))
+		}
+
+		if s.current.Len() == 0 && s.sentence.Len() == 0 {
+			s.start, s.timed = cue.Start-s.part.Offset, !cue.Has(subtitles.FlagUntimed)
+		}
+		s.labelled = labelled
+		if labelled {
+			s.current.WriteString(text)
+		} else {
+			s.sentence.WriteString(joinSeparator(s.sentence.String(), text) + text)
+			s.splitSentences()
+		}
+		prev = cue
+	}
+	s.endParagraph()
+	return s.paragraphs
+}
+
+// FormatParagraphs 把段落渲染为以空行分隔的文本；timestamps 为真时在每段前加上开始时间，
+// 合并的分 P 字幕中时间写作 "P3 12:30"。有说话人的段落以 "姓名：" 开头
+func FormatParagraphs(paragraphs []Paragraph, timestamps bool) string {
+	var out strings.Builder
+	for i, paragraph := range paragraphs {
+		if i > 0 {
+			out.WriteString("\n\n")
+		}
+		if timestamps && paragraph.Timed {
+			if paragraph.Part != 0 {
+				fmt.Fprintf(&out, "[P%d %s] ", paragraph.Part, subtitles.FormatShortClock(paragraph.Start))
+			} else {
+				out.WriteString("[" + subtitles.FormatShortClock(paragraph.Start) + "] ")
+			}
+		}
+		if paragraph.Speaker != "" {
+			out.WriteString(paragraph.Speaker + "：")
+		}
+		out.WriteString(paragraph.Text)
+	}
+	return out.String()
+}
+
+// gap 返回两条字幕之间的停顿，任一条目没有时间信息时返回 -1
+func (s *segmenter) gap(prev, next *subtitles.Cue) time.Duration {
+	if prev.Has(subtitles.FlagUntimed) || next.Has(subtitles.FlagUntimed) {
+		return -1
+	}
+	return next.Start - prev.End
+}
+
+// boundary 根据停顿和长度决定两条对白之间是分段、断句还是补逗号
+func (s *segmenter) boundary(gap time.Duration) {
+	pending := strings.TrimSpace(s.sentence.String())
+	switch {
+	case gap >= s.opts.ParagraphPause,
+		s.opts.MaxParagraph > 0 && utf8.RuneCountInString(s.current.String()) >= s.opts.MaxParagraph:
+		s.endParagraph()
+	case gap >= s.opts.SentencePause,
+		s.opts.MaxSentence > 0 && utf8.RuneCountInString(pending) >= s.opts.MaxSentence:
+		s.endSentence()
+	case pending != "":
+		last, _ := utf8.DecodeLastRuneInString(pending)
+		if !isPause(last) && !isTerminal(last) {
+			s.sentence.WriteString(clauseMark(last))
+		}
+	}
+}
+
+// splitSentences 把当前句子中已经以句末标点结束的部分移入段落
+func (s *segmenter) splitSentences() {
+	pending := s.sentence.String()
+	end := -1
+	for i, r := range pending {
+		if isTerminal(r) && !isDecimalPoint(pending, i) {
+			end = i + utf8.RuneLen(r)
+		}
+	}
+	if end < 0 {
+		return
+	}
+	s.appendSentence(pending[:end])
+	s.sentence.Reset()
+	s.sentence.WriteString(strings.TrimSpace(pending[end:]))
+}
+
+// endSentence 结束当前句子，缺少句末标点时补上
+func (s *segmenter) endSentence() {
+	pending := strings.TrimSpace(s.sentence.String())
+	s.sentence.Reset()
+	if pending == "" {
+		return
+	}
+	last, size := utf8.DecodeLastRuneInString(pending)
+	switch {
+	case isTerminal(last), last == '…':
+	case isPause(last):
+		pending = pending[:len(pending)-size] + terminalMark(pending[:len(pending)-size])
+	default:
+		pending += terminalMark(pending)
+	}
+	s.appendSentence(pending)
+}
+
+// appendSentence 把一个完整的句子追加到当前段落
+func (s *segmenter) appendSentence(sentence string) {
+	sentence = strings.TrimSpace(sentence)
+	if sentence == "" {
+		return
+	}
+	s.current.WriteString(joinSeparator(s.current.String(), sentence) + sentence)
+}
+
+// endParagraph 结束当前句子和段落
+func (s *segmenter) endParagraph() {
+	s.endSentence()
+	if s.current.Len() > 0 {
+		speaker := s.speaker
+		if s.labelled {
+			// 歌词和屏幕文字不属于任何说话人
+			speaker = ""
+		}
+		s.paragraphs = append(s.paragraphs, Paragraph{Start: s.start, Timed: s.timed, Part: s.part.Index, Speaker: speaker, Text: s.current.String()})
+	}
+	s.current.Reset()
+	s.labelled = false
+}
+
+// joinLines 把一条字幕的多行文本合并为一行
+func joinLines(text string) string {
+	var joined string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			joined += joinSeparator(joined, line) + line
+		}
+	}
+	return joined
+}
+
+// joinSeparator 返回拼接 left 和 right 时需要的分隔符：中文之间不加空格，其他情况加一个空格
+func joinSeparator(left, right string) string {
+	if left == "" {
+		return ""
+	}
+	last, _ := utf8.DecodeLastRuneInString(left)
+	first, _ := utf8.DecodeRuneInString(right)
+	if isCJKOrPunct(last) || isCJKOrPunct(first) {
+		return ""
+	}
+	return " "
+}
+
+// clauseMark 返回句中停顿处补的标点
+func clauseMark(last rune) string {
+	if isCJKOrPunct(last) {
+		return "，"
+	}
+	return ","
+}
+
+// terminalMark 返回句末补的标点：中文句子以疑问语气词结尾时补问号，否则补句号
+func terminalMark(sentence string) string {
+	last, _ := utf8.DecodeLastRuneInString(sentence)
+	if !isCJKOrPunct(last) {
+		return "."
+	}
+	switch last {
+	case '吗':
+		return "？"
+	case '么':
+		if !strings.HasSuffix(sentence, "这么") && !strings.HasSuffix(sentence, "那么") && !strings.HasSuffix(sentence, "多么") {
+			return "？"
+		}
+	case '呢':
+		for _, word := range questionWords {
+			if strings.Contains(sentence, word) {
+				return "？"
+			}
+		}
+	}
+	return "。"
+}
+
+// isDecimalPoint 判断 text[i] 处的 "." 是否为数字中的小数点
+func isDecimalPoint(text string, i int) bool {
+	if text[i] != '.' || i == 0 || i+1 >= len(text) {
+		return false
+	}
+	return text[i-1] >= '0' && text[i-1] <= '9' && text[i+1] >= '0' && text[i+1] <= '9'
+}
@@ -0,0 +1,112 @@
+package normalize
+
+import (
+	"bilibili_subtitle/internal/subtitles"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// timedCues builds cues from (start ms, end ms, text) triples.
+func timedCues(entries ...interface{}) *subtitles.Transcript {
+	transcript := &subtitles.Transcript{}
+	for i := 0; i+2 < len(entries); i += 3 {
+		transcript.Cues = append(transcript.Cues, subtitles.Cue{
+			Index: len(transcript.Cues) + 1,
+			Start: time.Duration(entries[i].(int)) * time.Millisecond,
+			End:   time.Duration(entries[i+1].(int)) * time.Millisecond,
+			Text:  entries[i+2].(string),
+		})
+	}
+	return transcript
+}
+
+// TestSegment tests sentence and paragraph breaks from pauses, length and existing punctuation.
+func TestSegment(t *testing.T) {
+	transcript := timedCues(
+		0, 1000, "大家好",
+		1100, 2500, "欢迎来到我的频道",
+		3500, 5000, "今天我们聊一聊字幕",
+		5100, 6000, "你们知道怎么做吗",
+		9000, 10000, "好的。那我们开始吧",
+		10100, 11000, "第一步是下载",
+	)
+	got := Segment(transcript, DefaultSegmentOptions)
+	want := []Paragraph{
+		{Start: 0, Timed: true, Text: "大家好，欢迎来到我的频道。今天我们聊一聊字幕，你们知道怎么做吗？"},
+		{Start: 9 * time.Second, Timed: true, Text: "好的。那我们开始吧，第一步是下载。"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	if text, want := FormatParagraphs(got, true), "[00:00] "+want[0].Text+"\n\n[00:09] "+want[1].Text; text != want {
+		t.Errorf("FormatParagraphs = %q, want %q", text, want)
+	}
+}
+
+// TestSegmentLimits tests length limits, English spacing and labelled cues.
+func TestSegmentLimits(t *testing.T) {
+	opts := SegmentOptions{SentencePause: time.Second, ParagraphPause: 5 * time.Second, MaxSentence: 10, MaxParagraph: 20}
+	transcript := timedCues(
+		0, 1000, "this is a long sentence",
+		1000, 2000, "that keeps going",
+		2000, 3000, "一二三四五六七八九十",
+		3000, 4000, "十一十二",
+		4000, 5000, "[歌词] 啦啦啦",
+		5000, 6000, "[歌词] 哒哒哒",
+		6000, 7000, "再见",
+	)
+	transcript.Cues[4].Flags = subtitles.FlagKaraoke
+	transcript.Cues[5].Flags = subtitles.FlagKaraoke
+
+	var got []string
+	for _, paragraph := range Segment(transcript, opts) {
+		got = append(got, paragraph.Text)
+	}
+	want := []string{
+		"this is a long sentence. that keeps going.",
+		"一二三四五六七八九十。十一十二。",
+		"[歌词] 啦啦啦\n[歌词] 哒哒哒",
+		"再见。",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+// TestSegmentParts tests part markers and part-relative timestamps in merged transcripts.
+func TestSegmentParts(t *testing.T) {
+	p1 := timedCues(0, 1000, "第一部分", 1100, 2000, "讲完了")
+	p2 := timedCues(30000, 31000, "第二部分开始")
+	merged, err := subtitles.Merge([]*subtitles.Transcript{p1, p2}, subtitles.MergeOptions{Titles: []string{"", "实战"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := FormatParagraphs(Segment(merged, DefaultSegmentOptions), true)
+	want := "【P1】\n\n[P1 00:00] 第一部分，讲完了。\n\n【P2 实战】\n\n[P2 00:30] 第二部分开始。"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+// TestSegmentSpeakers tests that speaker changes start a new paragraph labelled with the speaker.
+func TestSegmentSpeakers(t *testing.T) {
+	transcript := timedCues(
+		0, 1000, "欢迎收听",
+		1100, 2000, "今天请到了一位嘉宾",
+		2100, 3000, "大家好",
+		3100, 4000, "我是小王",
+		4100, 5000, "欢迎",
+	)
+	for i, speaker := range []string{"主持人", "", "嘉宾", "", "主持人"} {
+		transcript.Cues[i].Speaker = speaker
+	}
+
+	got := FormatParagraphs(Segment(transcript, DefaultSegmentOptions), false)
+	want := "主持人：欢迎收听，今天请到了一位嘉宾。\n\n嘉宾：大家好，我是小王。\n\n主持人：欢迎。"
+	if got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,335 @@
+// Package stats 从字幕条目计算确定性的统计数据（语音时长、语速、停顿、词汇），
+// 不需要调用模型，便于在不同视频之间比较
+package stats
+
+import (
+	"bilibili_subtitle/internal/normalize"
+	"bilibili_subtitle/internal/subtitles"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Options 是统计的参数
+type Options struct {
+	Window    time.Duration // 计算语速变化的时间窗口
+	TopPauses int           // 列出的最长停顿个数
+	TopTerms  int           // 列出的高频词个数
+
+	// MusicThreshold 与 music 处理的阈值相同：BCC music 字段不低于该值的条目视为歌词，不参与统计。
+	// 字幕是否已经过 music 处理都使用这一规则，stats 命令和分析结果中的统计因此一致
+	MusicThreshold float64
+}
+
+// DefaultOptions 是默认参数
+var DefaultOptions = Options{
+	Window:    time.Minute,
+	TopPauses: 5,
+	TopTerms:  20,
+
+	MusicThreshold: normalize.DefaultOptions.MusicThreshold,
+}
+
+// Report 是一份字幕的统计数据，时间均以秒为单位
+type Report struct {
+	Cues           int         `json:"cues"`
+	Duration       float64     `json:"duration_seconds"` // 从 0 到最后一条字幕结束
+	Speech         float64     `json:"speech_seconds"`   // 有字幕显示的时间（重叠部分只算一次）
+	Silence        float64     `json:"silence_seconds"`
+	SpeechRatio    float64     `json:"speech_ratio"`
+	Characters     int         `json:"characters"`       // 不含空白和标点
+	CharsPerMinute float64     `json:"chars_per_minute"` // 按语音时长计算
+	Rate           []RatePoint `json:"rate_over_time"`
+	LongestPauses  []Pause     `json:"longest_pauses"`
+	Vocabulary     int         `json:"vocabulary"` // 不同汉字和不同英文单词的个数
+	TopTerms       []Term      `json:"top_terms"`
+	Speakers       []Speaker   `json:"speakers"` // 按首次发言的顺序，没有标注说话人时为空
+}
+
+// RatePoint 是一个时间窗口内的语速
+type RatePoint struct {
+	Start          float64 `json:"start_seconds"`
+	CharsPerMinute float64 `json:"chars_per_minute"`
+}
+
+// Pause 是两条字幕之间的一段停顿
+type Pause struct {
+	Start    float64 `json:"start_seconds"`
+	End      float64 `json:"end_seconds"`
+	Duration float64 `json:"seconds"`
+}
+
+// Speaker 是一位说话人的统计数据
+type Speaker struct {
+	Name           string  `json:"name"`
+	Cues           int     `json:"cues"`
+	Turns          int     `json:"turns"` // 连续发言算作一次
+	Speech         float64 `json:"speech_seconds"`
+	Share          float64 `json:"speech_share"` // 占所有说话人发言时长的比例
+	Characters     int     `json:"characters"`
+	CharsPerMinute float64 `json:"chars_per_minute"`
+}
+
+// Term 是一个高频词及其出现次数
+type Term struct {
+	Term  string `json:"term"`
+	Count int    `json:"count"`
+}
+
+// stopChars 是不参与中文词频统计的虚词和代词，含有它们的双字组合会被跳过
+const stopChars = "的了是我你他她它们这那就都也在有和与及不吗呢吧啊呀哦嗯个一么什怎样还很又没对把被给让着过到说要会能去来上下里啦嘛哈"
+
+// stopWords 是不参与英文词频统计的常见词
+var stopWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "but": true, "of": true, "to": true, "in": true,
+	"on": true, "at": true, "for": true, "with": true, "is": true, "are": true, "was": true, "were": true, "be": true,
+	"it": true, "this": true, "that": true, "i": true, "you": true, "he": true, "she": true, "we": true, "they": true,
+	"my": true, "your": true, "so": true, "do": true, "not": true, "s": true, "t": true, "just": true,
+}
+
+// notSpeech 是不属于说话内容的条目：注释、歌词和屏幕文字（music 处理会给后两者加上 "[歌词]" 等标记）
+const notSpeech = subtitles.FlagComment | subtitles.FlagKaraoke | subtitles.FlagSign
+
+// Compute 计算字幕的统计数据；注释、歌词和屏幕文字不参与统计，没有时间信息的条目只计入字数和词汇。
+// 还没有经过 music 处理的 BCC 歌词和屏幕文字按 opts.MusicThreshold 识别
+func Compute(transcript *subtitles.Transcript, opts Options) *Report {
+	transcript = normalize.MusicPass{Policy: normalize.MusicTag, Threshold: opts.MusicThreshold}.Apply(transcript)
+	report := &Report{Rate: []RatePoint{}, LongestPauses: []Pause{}, TopTerms: []Term{}, Speakers: computeSpeakers(transcript)}
+
+	var timed []subtitles.Cue
+	chars := map[rune]bool{}
+	words := map[string]bool{}
+	terms := map[string]int{}
+	for _, cue := range transcript.Cues {
+		if cue.Has(notSpeech) {
+			continue
+		}
+		report.Cues++
+		report.Characters += countChars(cue.Text)
+		collectTerms(cue.Text, chars, words, terms)
+		// BCC 中偶尔有 "from": -1 之类的负数时间，从 0 开始计算
+		cue.Start = max(cue.Start, 0)
+		if !cue.Has(subtitles.FlagUntimed) && cue.End > cue.Start {
+			timed = append(timed, cue)
+		}
+	}
+	report.Vocabulary = len(chars) + len(words)
+	report.TopTerms = topTerms(terms, opts.TopTerms)
+
+	if len(timed) == 0 {
+		return report
+	}
+	sort.SliceStable(timed, func(i, j int) bool { return timed[i].Start < timed[j].Start })
+
+	// 合并重叠的条目得到语音区间，区间之间的空白即停顿
+	var speech time.Duration
+	var pauses []Pause
+	spanStart, spanEnd := timed[0].Start, timed[0].End
+	for _, cue := range timed[1:] {
+		if cue.Start > spanEnd {
+			speech += spanEnd - spanStart
+			pauses = append(pauses, Pause{Start: seconds(spanEnd), End: seconds(cue.Start), Duration: seconds(cue.Start - spanEnd)})
+			spanStart = cue.Start
+		}
+		if cue.End > spanEnd {
+			spanEnd = cue.End
+		}
+	}
+	speech += spanEnd - spanStart
+
+	report.Duration = seconds(spanEnd)
+	report.Speech = seconds(speech)
+	report.Silence = round(report.Duration - report.Speech)
+	if report.Duration > 0 {
+		report.SpeechRatio = round(report.Speech / report.Duration)
+	}
+	if speech > 0 {
+		report.CharsPerMinute = round(float64(report.Characters) / speech.Minutes())
+	}
+
+	sort.SliceStable(pauses, func(i, j int) bool { return pauses[i].Duration > pauses[j].Duration })
+	if len(pauses) > opts.TopPauses {
+		pauses = pauses[:opts.TopPauses]
+	}
+	report.LongestPauses = append(report.LongestPauses, pauses...)
+
+	if opts.Window > 0 {
+		windows := make([]int, int(spanEnd/opts.Window)+1)
+		for _, cue := range timed {
+			windows[int(cue.Start/opts.Window)] += countChars(cue.Text)
+		}
+		for i, n := range windows {
+			report.Rate = append(report.Rate, RatePoint{
+				Start:          seconds(time.Duration(i) * opts.Window),
+				CharsPerMinute: round(float64(n) / opts.Window.Minutes()),
+			})
+		}
+	}
+	return report
+}
+
+// Markdown 把统计数据渲染为 analysis.md 中的一节内容
+func (r *Report) Markdown() string {
+	var out strings.Builder
+	fmt.Fprintf(&out, "- 字幕条数：%d\n", r.Cues)
+	fmt.Fprintf(&out, "- 总时长：%s，语音 %s，静默 %s（语音占比 %.0f%%）\n",
+		formatSeconds(r.Duration), formatSeconds(r.Speech), formatSeconds(r.Silence), r.SpeechRatio*100)
+	fmt.Fprintf(&out, "- 字数：%d，平均语速 %.0f 字/分钟\n", r.Characters, r.CharsPerMinute)
+	fmt.Fprintf(&out, "- 词汇量：%d\n", r.Vocabulary)
+
+	if len(r.Rate) > 0 {
+		out.WriteString("\n### 语速变化\n\n| 开始 | 字/分钟 |\n| --- | --- |\n")
+		for _, point := range r.Rate {
+			fmt.Fprintf(&out, "| %s | %.0f |\n", formatSeconds(point.Start), point.CharsPerMinute)
+		}
+	}
+	if len(r.LongestPauses) > 0 {
+		out.WriteString("\n### 最长停顿\n\n| 开始 | 结束 | 时长（秒） |\n| --- | --- | --- |\n")
+		for _, pause := range r.LongestPauses {
+			fmt.Fprintf(&out, "| %s | %s | %.1f |\n", formatSeconds(pause.Start), formatSeconds(pause.End), pause.Duration)
+		}
+	}
+	if len(r.Speakers) > 0 {
+		out.WriteString("\n### 说话人\n\n| 说话人 | 发言次数 | 时长 | 占比 | 字数 | 字/分钟 |\n| --- | --- | --- | --- | --- | --- |\n")
+		for _, speaker := range r.Speakers {
+			fmt.Fprintf(&out, "| %s | %d | %s | %.0f%% | %d | %.0f |\n", speaker.Name, speaker.Turns,
+				formatSeconds(speaker.Speech), speaker.Share*100, speaker.Characters, speaker.CharsPerMinute)
+		}
+	}
+	if len(r.TopTerms) > 0 {
+		out.WriteString("\n### 高频词\n\n")
+		for i, term := range r.TopTerms {
+			if i > 0 {
+				out.WriteString("、")
+			}
+			fmt.Fprintf(&out, "%s（%d）", term.Term, term.Count)
+		}
+		out.WriteString("\n")
+	}
+	return out.String()
+}
+
+// computeSpeakers 统计每位说话人的发言次数、时长和字数；没有标注说话人的条目不计入
+func computeSpeakers(transcript *subtitles.Transcript) []Speaker {
+	speakers := []Speaker{}
+	index := map[string]int{}
+	speech := map[string]time.Duration{}
+	var total time.Duration
+	last := ""
+	for _, cue := range transcript.Cues {
+		if cue.Speaker == "" || cue.Has(notSpeech) {
+			continue
+		}
+		i, ok := index[cue.Speaker]
+		if !ok {
+			i = len(speakers)
+			index[cue.Speaker] = i
+			speakers = append(speakers, Speaker{Name: cue.Speaker})
+		}
+		speaker := &speakers[i]
+		speaker.Cues++
+		speaker.Characters += countChars(cue.Text)
+		if cue.Speaker != last {
+			speaker.Turns++
+			last = cue.Speaker
+		}
+		if !cue.Has(subtitles.FlagUntimed) && cue.End > cue.Start {
+			speech[cue.Speaker] += cue.Duration()
+			total += cue.Duration()
+		}
+	}
+
+	for i := range speakers {
+		d := speech[speakers[i].Name]
+		speakers[i].Speech = seconds(d)
+		if total > 0 {
+			speakers[i].Share = round(float64(d) / float64(total))
+		}
+		if d > 0 {
+			speakers[i].CharsPerMinute = round(float64(speakers[i].Characters) / d.Minutes())
+		}
+	}
+	return speakers
+}
+
+// countChars 返回文本中除空白和标点以外的字数
+func countChars(text string) int {
+	n := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) && !unicode.IsPunct(r) && !unicode.IsSymbol(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// collectTerms 统计不同的汉字、英文单词，以及中文双字组合和英文单词的出现次数
+func collectTerms(text string, chars map[rune]bool, words map[string]bool, terms map[string]int) {
+	var word []rune
+	var prev rune
+	flushWord := func() {
+		if len(word) > 0 {
+			w := strings.ToLower(string(word))
+			words[w] = true
+			if !stopWords[w] && len(word) > 1 {
+				terms[w]++
+			}
+			word = word[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			chars[r] = true
+			if prev != 0 && !strings.ContainsRune(stopChars, prev) && !strings.ContainsRune(stopChars, r) {
+				terms[string([]rune{prev, r})]++
+			}
+			prev = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flushWord()
+		}
+		prev = 0
+	}
+	flushWord()
+}
+
+// topTerms 返回出现至少两次的前 n 个高频词，次数相同时按字典序排列
+func topTerms(terms map[string]int, n int) []Term {
+	result := []Term{}
+	for term, count := range terms {
+		if count >= 2 {
+			result = append(result, Term{Term: term, Count: count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Term < result[j].Term
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
+
+func seconds(d time.Duration) float64 {
+	return round(d.Seconds())
+}
+
+// round 保留三位小数
+func round(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
+
+// formatSeconds 把秒数格式化为 "mm:ss"，超过一小时时为 "h:mm:ss"，见 subtitles.FormatShortClock
+func formatSeconds(s float64) string {
+	return subtitles.FormatShortClock(time.Duration(math.Round(s*1000)) * time.Millisecond)
+}
@@ -0,0 +1,138 @@
+package stats
+
+import (
+	"bilibili_subtitle/internal/normalize"
+	"bilibili_subtitle/internal/subtitles"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCompute tests speech time, pauses, speech rate and term counts.
+func TestCompute(t *testing.T) {
+	s := time.Second
+	transcript := &subtitles.Transcript{Cues: []subtitles.Cue{
+		{Start: 0, End: 2 * s, Text: "字幕格式很重要"},
+		{Start: 1 * s, End: 3 * s, Text: "字幕格式, OK"},
+		{Start: 10 * s, End: 12 * s, Text: "我们讲字幕"},
+		{Start: 70 * s, End: 72 * s, Text: "OK Subtitle formats"},
+		{Text: "注释", Flags: subtitles.FlagComment},
+	}}
+
+	report := Compute(transcript, DefaultOptions)
+	if report.Cues != 4 || report.Duration != 72 || report.Speech != 7 || report.Silence != 65 {
+		t.Errorf("timing = %+v", report)
+	}
+	if report.Characters != 7+6+5+17 {
+		t.Errorf("characters = %d", report.Characters)
+	}
+	if want := []Pause{{Start: 12, End: 70, Duration: 58}, {Start: 3, End: 10, Duration: 7}}; !reflect.DeepEqual(report.LongestPauses, want) {
+		t.Errorf("pauses = %+v, want %+v", report.LongestPauses, want)
+	}
+	if want := []RatePoint{{Start: 0, CharsPerMinute: 18}, {Start: 60, CharsPerMinute: 17}}; !reflect.DeepEqual(report.Rate, want) {
+		t.Errorf("rate = %+v, want %+v", report.Rate, want)
+	}
+	if want := []Term{{"字幕", 3}, {"ok", 2}, {"幕格", 2}, {"格式", 2}}; !reflect.DeepEqual(report.TopTerms, want) {
+		t.Errorf("terms = %+v, want %+v", report.TopTerms, want)
+	}
+
+	markdown := report.Markdown()
+	for _, want := range []string{"- 字幕条数：4", "语音 00:07", "| 00:12 | 01:10 | 58.0 |", "字幕（3）"} {
+		if !strings.Contains(markdown, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, markdown)
+		}
+	}
+}
+
+// TestComputeSkipsLyrics tests that lyrics and on-screen text labelled by the music pass are not counted as speech.
+func TestComputeSkipsLyrics(t *testing.T) {
+	s := time.Second
+	transcript := &subtitles.Transcript{Cues: []subtitles.Cue{
+		{Start: 0, End: 4 * s, Text: "歌词第一句", Music: 0.9},
+		{Start: 4 * s, End: 8 * s, Text: "歌词第二句", Music: 0.9},
+		{Start: 8 * s, End: 9 * s, Text: "片名", Location: 8},
+		{Start: 10 * s, End: 12 * s, Text: "大家好"},
+	}}
+	labelled := normalize.MusicPass{Policy: normalize.MusicTag, Threshold: 0.5}.Apply(transcript)
+
+	report := Compute(labelled, DefaultOptions)
+	if report.Cues != 1 || report.Characters != 3 || report.Speech != 2 || report.Duration != 12 {
+		t.Errorf("report = %+v", report)
+	}
+	for _, term := range report.TopTerms {
+		if strings.Contains(term.Term, "歌") || strings.Contains(term.Term, "屏幕") {
+			t.Errorf("label counted as a term: %+v", report.TopTerms)
+		}
+	}
+}
+
+// TestComputeLyricsBothPaths tests that BCC lyrics are left out whether or not the transcript went through the normalisation pipeline.
+func TestComputeLyricsBothPaths(t *testing.T) {
+	data := `{"lang":"zh","body":[` +
+		`{"from":0,"to":4,"location":2,"content":"歌词第一句","music":0.9},` +
+		`{"from":4,"to":8,"location":2,"content":"歌词第二句","music":0.8},` +
+		`{"from":10,"to":12,"location":2,"content":"大家好","music":0.1}]}`
+	transcript, err := subtitles.ParseAll(&subtitles.NewJSONSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	// The stats command counts the parsed file, the analysis counts the normalised transcript.
+	raw := Compute(subtitles.ExtractSpeakers(transcript), DefaultOptions)
+	pipeline, err := normalize.NewPipeline(normalize.DefaultPasses, normalize.DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalized, _ := pipeline.Run(transcript)
+	analysed := Compute(normalized, DefaultOptions)
+
+	if raw.Cues != 1 || raw.Characters != 3 || raw.Speech != 2 {
+		t.Errorf("stats command report = %+v", raw)
+	}
+	if !reflect.DeepEqual(raw, analysed) {
+		t.Errorf("reports differ:\n%+v\n%+v", raw, analysed)
+	}
+}
+
+// TestComputeNegativeStart tests that cues starting before zero are clamped instead of crashing the rate windows.
+func TestComputeNegativeStart(t *testing.T) {
+	s := time.Second
+	transcript := &subtitles.Transcript{Cues: []subtitles.Cue{
+		{Start: -1 * s, End: 2 * s, Text: "开头"},
+		{Start: -3 * s, End: -2 * s, Text: "之前"},
+		{Start: 3 * s, End: 4 * s, Text: "结尾"},
+	}}
+
+	report := Compute(transcript, DefaultOptions)
+	if report.Cues != 3 || report.Speech != 3 || report.Duration != 4 {
+		t.Errorf("report = %+v", report)
+	}
+	if len(report.Rate) != 1 || report.Rate[0].Start != 0 {
+		t.Errorf("rate = %+v", report.Rate)
+	}
+}
+
+// TestComputeSpeakers tests per-speaker turns, talk time and share.
+func TestComputeSpeakers(t *testing.T) {
+	s := time.Second
+	transcript := &subtitles.Transcript{Cues: []subtitles.Cue{
+		{Start: 0, End: 3 * s, Speaker: "主持人", Text: "欢迎收听"},
+		{Start: 3 * s, End: 6 * s, Speaker: "主持人", Text: "今天的嘉宾"},
+		{Start: 6 * s, End: 7 * s, Speaker: "嘉宾", Text: "你好"},
+		{Start: 7 * s, End: 8 * s, Text: "（掌声）"},
+		{Start: 8 * s, End: 10 * s, Speaker: "主持人", Text: "请坐"},
+	}}
+
+	report := Compute(transcript, DefaultOptions)
+	want := []Speaker{
+		{Name: "主持人", Cues: 3, Turns: 2, Speech: 8, Share: 0.889, Characters: 11, CharsPerMinute: 82.5},
+		{Name: "嘉宾", Cues: 1, Turns: 1, Speech: 1, Share: 0.111, Characters: 2, CharsPerMinute: 120},
+	}
+	if !reflect.DeepEqual(report.Speakers, want) {
+		t.Errorf("speakers = %+v, want %+v", report.Speakers, want)
+	}
+	if markdown := report.Markdown(); !strings.Contains(markdown, "| 主持人 | 2 | 00:08 | 89% | 11 | 82 |") {
+		t.Errorf("Markdown() missing speaker row:\n%s", markdown)
+	}
+}
@@ -0,0 +1,419 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ASSSubtitleParser 实现了 SubtitleParser 接口，专门处理 ASS/SSA 格式
+type ASSSubtitleParser struct{}
+
+// ASSSubtitleWriter 实现了 SubtitleWriter 接口，输出 ASS 格式
+type ASSSubtitleWriter struct{}
+
+// ASSStyle 是 [V4+ Styles] 中的一条样式定义
+type ASSStyle struct {
+	Name          string
+	FontName      string
+	FontSize      float64
+	PrimaryColour string
+	Alignment     int
+	Fields        map[string]string // 所有原始字段，键为 Format 行中的字段名
+}
+
+var (
+	// 字幕组常用的屏幕文字（特效字、标题、注释）样式名；英文要求是完整的词，
+	// 避免把 "Subtitle"、"Design" 之类的对白样式当作屏幕文字
+	assSignStyle = regexp.MustCompile(`(?i)(^|[^a-z])(signs?|titles?|screen|notes?)([^a-z]|$)|注释|标注|屏幕|说明`)
+	// 卡拉 OK / 歌词样式名
+	assKaraokeStyle = regexp.MustCompile(`(?i)kara|lyric|song|(^|[^a-z])(op|ed)([^a-z]|$)|歌词`)
+	// 定位类覆盖标签通常只出现在屏幕文字中
+	assPositionTag = regexp.MustCompile(`\\(pos|move|org|clip|iclip)\(`)
+	assKaraokeTag  = regexp.MustCompile(`\\(k|K|kf|ko)\d`)
+	assDrawingTag  = regexp.MustCompile(`\\p(\d+)`)
+)
+
+// 写出 ASS 时使用的画布大小和字段顺序
+const (
+	assPlayResX = 1920
+	assPlayResY = 1080
+	// BCC 的 font_size 是相对字号，0.4 大致相当于 1080p 画布上的 48 号字
+	assBCCFontScale = 120
+)
+
+var (
+	assStyleFormat = []string{"Name", "Fontname", "Fontsize", "PrimaryColour", "SecondaryColour", "OutlineColour", "BackColour",
+		"Bold", "Italic", "Underline", "StrikeOut", "ScaleX", "ScaleY", "Spacing", "Angle",
+		"BorderStyle", "Outline", "Shadow", "Alignment", "MarginL", "MarginR", "MarginV", "Encoding"}
+	assEventFormat = []string{"Layer", "Start", "End", "Style", "Name", "MarginL", "MarginR", "MarginV", "Effect", "Text"}
+	// assDefaultStyle 是没有样式信息时使用的默认样式
+	assDefaultStyle = map[string]string{
+		"Name": "Default", "Fontname": "Microsoft YaHei", "Fontsize": "48",
+		"PrimaryColour": "&H00FFFFFF", "SecondaryColour": "&H000000FF", "OutlineColour": "&H00000000", "BackColour": "&H80000000",
+		"Bold": "0", "Italic": "0", "Underline": "0", "StrikeOut": "0", "ScaleX": "100", "ScaleY": "100", "Spacing": "0", "Angle": "0",
+		"BorderStyle": "1", "Outline": "2", "Shadow": "0", "Alignment": "2", "MarginL": "20", "MarginR": "20", "MarginV": "40", "Encoding": "1",
+	}
+)
+
+// Parse 解析 ASS/SSA 格式字幕文件
+func (p *ASSSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	scanner := newLineScanner(r)
+	meta.Format = FormatASS
+
+	section := ""
+	var styleFormat, eventFormat []string
+	signStyles := map[string]bool{}
+	karaokeStyles := map[string]bool{}
+	lineNo, count := 0, 0
+
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		switch section {
+		case "[script info]":
+			if key == "Language" {
+				meta.Lang = value
+			}
+		case "[v4+ styles]", "[v4 styles]":
+			switch key {
+			case "Format":
+				styleFormat = splitASSFormat(value)
+			case "Style":
+				style := parseASSStyle(styleFormat, value)
+				meta.Styles = append(meta.Styles, style)
+				signStyles[style.Name] = assSignStyle.MatchString(style.Name)
+				karaokeStyles[style.Name] = assKaraokeStyle.MatchString(style.Name)
+			}
+		case "[events]":
+			switch key {
+			case "Format":
+				eventFormat = splitASSFormat(value)
+			case "Dialogue", "Comment":
+				if eventFormat == nil {
+					return fmt.Errorf("event before Format line at line %d", lineNo)
+				}
+				fields := splitASSFields(eventFormat, value)
+
+				cue := Cue{
+					Index:   count + 1,
+					Style:   strings.TrimPrefix(fields["Style"], "*"),
+					Speaker: fields["Name"],
+				}
+				var err error
+				if cue.Start, err = parseASSTimestamp(fields["Start"]); err != nil {
+					return fmt.Errorf("error parsing start time at line %d: %v", lineNo, err)
+				}
+				if cue.End, err = parseASSTimestamp(fields["End"]); err != nil {
+					return fmt.Errorf("error parsing end time at line %d: %v", lineNo, err)
+				}
+
+				rawText := fields["Text"]
+				cue.Text = stripASSTags(rawText)
+				if key == "Comment" {
+					cue.Flags |= FlagComment
+				}
+				if signStyles[cue.Style] || assPositionTag.MatchString(rawText) || isASSDrawing(rawText) {
+					cue.Flags |= FlagSign
+				}
+				if karaokeStyles[cue.Style] || assKaraokeTag.MatchString(rawText) {
+					cue.Flags |= FlagKaraoke
+				}
+				if cue.Text == "" {
+					continue
+				}
+				count++
+				if err := emit(cue); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading subtitle file: %v", err)
+	}
+
+	return nil
+}
+
+// Write 将字幕写出为 ASS 格式。来源为 ASS 时保留原有样式；
+// 否则生成一个 Default 样式，有 BCC 显示设置时据此设置字号、文字颜色和背景
+func (w *ASSSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	writer := bufio.NewWriter(out)
+
+	writer.WriteString("[Script Info]\nScriptType: v4.00+\n")
+	fmt.Fprintf(writer, "PlayResX: %d\nPlayResY: %d\n", assPlayResX, assPlayResY)
+	writer.WriteString("WrapStyle: 0\nScaledBorderAndShadow: yes\n")
+	if transcript.Lang != "" {
+		fmt.Fprintf(writer, "Language: %s\n", transcript.Lang)
+	}
+
+	styles := transcript.Styles
+	if len(styles) == 0 {
+		styles = []ASSStyle{bccToASSStyle(transcript.BCC)}
+	}
+	fmt.Fprintf(writer, "\n[V4+ Styles]\nFormat: %s\n", strings.Join(assStyleFormat, ", "))
+	for _, style := range styles {
+		values := make([]string, len(assStyleFormat))
+		for i, name := range assStyleFormat {
+			value, ok := style.Fields[name]
+			if !ok {
+				value = assDefaultStyle[name]
+			}
+			values[i] = value
+		}
+		values[0] = style.Name
+		fmt.Fprintf(writer, "Style: %s\n", strings.Join(values, ","))
+	}
+
+	fmt.Fprintf(writer, "\n[Events]\nFormat: %s\n", strings.Join(assEventFormat, ", "))
+	for _, cue := range transcript.Cues {
+		kind := "Dialogue"
+		if cue.Has(FlagComment) {
+			kind = "Comment"
+		}
+		style := cue.Style
+		if style == "" {
+			style = styles[0].Name
+		}
+		fmt.Fprintf(writer, "%s: 0,%s,%s,%s,%s,0,0,0,,%s\n", kind, formatASSTimestamp(cue.Start), formatASSTimestamp(cue.End),
+			style, strings.ReplaceAll(cue.Speaker, ",", "，"), escapeASSText(cue.Text))
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing ASS: %v", err)
+	}
+	return nil
+}
+
+// bccToASSStyle 根据 BCC 显示设置生成 Default 样式；meta 为 nil 时使用默认样式
+func bccToASSStyle(meta *BCCMeta) ASSStyle {
+	fields := make(map[string]string, len(assDefaultStyle))
+	for name, value := range assDefaultStyle {
+		fields[name] = value
+	}
+	if meta != nil {
+		if meta.FontSize > 0 {
+			fields["Fontsize"] = strconv.FormatFloat(meta.FontSize*assBCCFontScale, 'f', -1, 64)
+		}
+		if colour, ok := htmlToASSColour(meta.FontColor, 1); ok {
+			fields["PrimaryColour"] = colour
+		}
+		if colour, ok := htmlToASSColour(meta.BackgroundColor, meta.BackgroundAlpha); ok {
+			// BorderStyle 3 用不透明方框代替描边，对应 B 站字幕的背景色块
+			fields["BackColour"] = colour
+			fields["OutlineColour"] = colour
+			fields["BorderStyle"] = "3"
+		}
+	}
+	return parseASSStyle(assStyleFormat, assStyleLine(fields))
+}
+
+// assToBCCMeta 根据 ASS 样式推断 BCC 显示设置，优先使用名为 Default 的样式
+func assToBCCMeta(styles []ASSStyle) *BCCMeta {
+	if len(styles) == 0 {
+		return nil
+	}
+	style := styles[0]
+	for _, s := range styles {
+		if strings.EqualFold(s.Name, "Default") {
+			style = s
+			break
+		}
+	}
+
+	meta := defaultBCCMeta
+	if style.FontSize > 0 {
+		meta.FontSize = math.Round(style.FontSize/assBCCFontScale*100) / 100
+	}
+	if colour, _, ok := assToHTMLColour(style.PrimaryColour); ok {
+		meta.FontColor = colour
+	}
+	if style.Fields["BorderStyle"] == "3" {
+		if colour, alpha, ok := assToHTMLColour(style.Fields["BackColour"]); ok {
+			meta.BackgroundColor, meta.BackgroundAlpha = colour, alpha
+		}
+	}
+	return &meta
+}
+
+// assStyleLine 按 assStyleFormat 的顺序拼出 Style 行的值
+func assStyleLine(fields map[string]string) string {
+	values := make([]string, len(assStyleFormat))
+	for i, name := range assStyleFormat {
+		values[i] = fields[name]
+	}
+	return strings.Join(values, ",")
+}
+
+// htmlToASSColour 将 "#RRGGBB" 和不透明度转换为 ASS 的 "&HAABBGGRR"（ASS 中 00 表示不透明）
+func htmlToASSColour(colour string, opacity float64) (string, bool) {
+	colour = strings.TrimPrefix(colour, "#")
+	if len(colour) != 6 {
+		return "", false
+	}
+	rgb, err := strconv.ParseUint(colour, 16, 32)
+	if err != nil {
+		return "", false
+	}
+	alpha := int(math.Round((1 - opacity) * 255))
+	return fmt.Sprintf("&H%02X%02X%02X%02X", alpha, rgb&0xFF, rgb>>8&0xFF, rgb>>16), true
+}
+
+// assToHTMLColour 将 ASS 的 "&HAABBGGRR" 转换为 "#RRGGBB" 和不透明度
+func assToHTMLColour(colour string) (string, float64, bool) {
+	colour = strings.TrimSuffix(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(colour)), "&H"), "&")
+	value, err := strconv.ParseUint(colour, 16, 32)
+	if err != nil || colour == "" {
+		return "", 0, false
+	}
+	alpha := value >> 24 & 0xFF
+	opacity := math.Round((1-float64(alpha)/255)*100) / 100
+	return fmt.Sprintf("#%02X%02X%02X", value&0xFF, value>>8&0xFF, value>>16&0xFF), opacity, true
+}
+
+// formatASSTimestamp 将时间格式化为 "H:MM:SS.cc"
+func formatASSTimestamp(d time.Duration) string {
+	cs := int64(d / (10 * time.Millisecond))
+	return fmt.Sprintf("%d:%02d:%02d.%02d", cs/360000, cs/6000%60, cs/100%60, cs%100)
+}
+
+// escapeASSText 把换行转换为 \N，并把花括号替换为全角字符，避免被当作覆盖标签
+func escapeASSText(text string) string {
+	return strings.NewReplacer("\n", `\N`, "{", "｛", "}", "｝").Replace(text)
+}
+
+// splitASSFormat 解析 "Format: Layer, Start, End, ..." 行中的字段名
+func splitASSFormat(value string) []string {
+	names := strings.Split(value, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+	return names
+}
+
+// splitASSFields 按 Format 行切分字段；最后一个字段（通常是 Text）可以包含逗号
+func splitASSFields(format []string, value string) map[string]string {
+	values := strings.SplitN(value, ",", len(format))
+	fields := make(map[string]string, len(format))
+	for i, name := range format {
+		if i < len(values) {
+			if name == "Text" {
+				fields[name] = values[i]
+			} else {
+				fields[name] = strings.TrimSpace(values[i])
+			}
+		}
+	}
+	return fields
+}
+
+// parseASSStyle 解析一条 Style 定义
+func parseASSStyle(format []string, value string) ASSStyle {
+	fields := splitASSFields(format, value)
+	style := ASSStyle{
+		Name:          fields["Name"],
+		FontName:      fields["Fontname"],
+		PrimaryColour: fields["PrimaryColour"],
+		Fields:        fields,
+	}
+	style.FontSize, _ = strconv.ParseFloat(fields["Fontsize"], 64)
+	style.Alignment, _ = strconv.Atoi(fields["Alignment"])
+	return style
+}
+
+// parseASSTimestamp 解析 "H:MM:SS.cc" 形式的时间（厘秒精度）
+func parseASSTimestamp(s string) (time.Duration, error) {
+	clock, frac, _ := strings.Cut(strings.TrimSpace(s), ".")
+	fields := strings.Split(clock, ":")
+	if len(fields) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var total time.Duration
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		total = total*60 + time.Duration(n)*time.Second
+	}
+	if frac != "" {
+		// 小数部分按十进制小数处理，".5" 与 ".50" 都表示半秒
+		for len(frac) < 3 {
+			frac += "0"
+		}
+		ms, err := strconv.Atoi(frac[:3])
+		if err != nil {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, nil
+}
+
+// stripASSTags 去掉 {\an8}、{\pos()} 等覆盖标签以及绘图指令，并把 \N、\n、\h 转换为普通字符
+func stripASSTags(text string) string {
+	var builder strings.Builder
+	drawing := false
+	for len(text) > 0 {
+		if text[0] == '{' {
+			end := strings.IndexByte(text, '}')
+			if end < 0 {
+				break
+			}
+			for _, m := range assDrawingTag.FindAllStringSubmatch(text[:end], -1) {
+				drawing = m[1] != "0"
+			}
+			text = text[end+1:]
+			continue
+		}
+		next := strings.IndexByte(text, '{')
+		if next < 0 {
+			next = len(text)
+		}
+		if !drawing {
+			builder.WriteString(text[:next])
+		}
+		text = text[next:]
+	}
+
+	replaced := strings.NewReplacer(`\N`, "\n", `\n`, "\n", `\h`, " ").Replace(builder.String())
+	lines := strings.Split(replaced, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if line = strings.TrimSpace(line); line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isASSDrawing 判断一条事件是否包含矢量绘图
+func isASSDrawing(text string) bool {
+	for _, m := range assDrawingTag.FindAllStringSubmatch(text, -1) {
+		if m[1] != "0" {
+			return true
+		}
+	}
+	return false
+}
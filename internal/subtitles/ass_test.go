@@ -0,0 +1,91 @@
+package subtitles
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestASSParserSample tests override tag stripping, comments, signs and karaoke detection.
+func TestASSParserSample(t *testing.T) {
+	file, err := os.Open("testdata/sample.ass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	transcript, err := ParseAll(&ASSSubtitleParser{}, file)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	if len(transcript.Styles) != 3 || transcript.Styles[1].Name != "Sign" || transcript.Styles[1].Alignment != 8 {
+		t.Errorf("unexpected styles: %+v", transcript.Styles)
+	}
+
+	type summary struct {
+		Text    string
+		Speaker string
+		Start   time.Duration
+		Flags   CueFlag
+	}
+	var got []summary
+	for _, cue := range transcript.Cues {
+		got = append(got, summary{cue.Text, cue.Speaker, cue.Start, cue.Flags})
+	}
+	want := []summary{
+		{"你好，世界\n第二行", "小明", 1500 * time.Millisecond, 0},
+		{"校对：这里需要改", "", 2 * time.Second, FlagComment},
+		{"第一话", "", 4 * time.Second, FlagSign},
+		{"啦啦啦", "", 5 * time.Second, FlagKaraoke},
+		{"好的，没问题", "小红", 7 * time.Second, 0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cues = %+v\nwant %+v", got, want)
+	}
+
+	if text := transcript.Without(FlagSign | FlagKaraoke).Text(); text != "小明：你好，世界, 第二行, \n小红：好的，没问题, " {
+		t.Errorf("filtered Text() = %q", text)
+	}
+}
+
+// TestASSSignStyleNames tests that sign styles are recognised by whole words only.
+func TestASSSignStyleNames(t *testing.T) {
+	styles := []struct {
+		name string
+		sign bool
+	}{
+		{"Subtitle", false},
+		{"Design", false},
+		{"Signature", false},
+		{"Footnotes", false},
+		{"Sign", true},
+		{"Signs_Top", true},
+		{"OP Title", true},
+		{"ED-Notes", true},
+		{"屏幕字", true},
+	}
+	var input strings.Builder
+	input.WriteString("[V4+ Styles]\nFormat: Name, Fontsize, Alignment\n")
+	for _, style := range styles {
+		input.WriteString("Style: " + style.name + ",48,2\n")
+	}
+	input.WriteString("\n[Events]\nFormat: Layer, Start, End, Style, Name, Text\n")
+	for _, style := range styles {
+		input.WriteString("Dialogue: 0,0:00:01.00,0:00:02.00," + style.name + ",,text\n")
+	}
+
+	transcript, err := ParseAll(&ASSSubtitleParser{}, strings.NewReader(input.String()))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(transcript.Cues) != len(styles) {
+		t.Fatalf("got %d cues, want %d", len(transcript.Cues), len(styles))
+	}
+	for i, style := range styles {
+		if got := transcript.Cues[i].Has(FlagSign); got != style.sign {
+			t.Errorf("style %q: sign = %v, want %v", style.name, got, style.sign)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// minTrackShare 是一种语言单独成轨所需的最低条目占比；占比更低的语言
+// 通常是夹杂在对白中的外语单词或短句，保留在所有轨道中
+const minTrackShare = 0.2
+
+// DetectLineLang 根据文字判断一行字幕的语言："zh"、"ja"、"ko"、"ru"，
+// 拉丁字母统一视为 "en"；没有可判断的文字（如纯数字、标点、音符）时返回空字符串。
+// 同时含有汉字和拉丁字母时，汉字数不少于英文单词数即视为中文
+func DetectLineLang(line string) string {
+	var han, kana, hangul, cyrillic, words int
+	inWord := false
+	for _, r := range line {
+		isLatin := unicode.Is(unicode.Latin, r)
+		switch {
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case isLatin && !inWord:
+			words++
+		}
+		inWord = isLatin
+	}
+
+	switch {
+	case kana > 0:
+		return "ja"
+	case hangul > 0:
+		return "ko"
+	case han > 0 && han >= words:
+		return "zh"
+	case cyrillic > 0 && cyrillic >= words:
+		return "ru"
+	case words > 0:
+		return "en"
+	}
+	return ""
+}
+
+// SplitTracks 把双语（或多语）字幕按行的语言拆成单语轨道，顺序与各语言首次出现的顺序一致，
+// 每条轨道的 Lang 为对应的语言。无法判断语言的行以及占比不足的语言会保留在所有轨道中。
+// 只有一种语言时返回只含原 Transcript 的切片
+func SplitTracks(transcript *Transcript) []*Transcript {
+	// 统计每种语言出现在多少条字幕中
+	var order []string
+	counts := map[string]int{}
+	total := 0
+	for _, cue := range transcript.Cues {
+		seen := map[string]bool{}
+		for _, line := range strings.Split(cue.Text, "\n") {
+			lang := DetectLineLang(line)
+			if lang == "" || seen[lang] {
+				continue
+			}
+			seen[lang] = true
+			if counts[lang] == 0 {
+				order = append(order, lang)
+			}
+			counts[lang]++
+		}
+		if len(seen) > 0 {
+			total++
+		}
+	}
+
+	var langs []string
+	for _, lang := range order {
+		if float64(counts[lang]) >= minTrackShare*float64(total) {
+			langs = append(langs, lang)
+		}
+	}
+	if len(langs) < 2 {
+		return []*Transcript{transcript}
+	}
+
+	tracks := make([]*Transcript, len(langs))
+	index := map[string]int{}
+	for i, lang := range langs {
+		track := *transcript
+		track.Lang = lang
+		track.Cues = nil
+		tracks[i] = &track
+		index[lang] = i
+	}
+
+	for _, cue := range transcript.Cues {
+		lines := make([][]string, len(langs))
+		for _, line := range strings.Split(cue.Text, "\n") {
+			if i, ok := index[DetectLineLang(line)]; ok {
+				lines[i] = append(lines[i], line)
+				continue
+			}
+			for i := range lines {
+				lines[i] = append(lines[i], line)
+			}
+		}
+		for i, trackLines := range lines {
+			text := strings.TrimSpace(strings.Join(trackLines, "\n"))
+			if text == "" {
+				continue
+			}
+			trackCue := cue
+			trackCue.Text = text
+			tracks[i].Cues = append(tracks[i].Cues, trackCue)
+		}
+	}
+	return tracks
+}
+
+// SelectTrack 返回指定语言的轨道；字幕不是多语字幕时原样返回
+func SelectTrack(transcript *Transcript, lang string) (*Transcript, error) {
+	tracks := SplitTracks(transcript)
+	if len(tracks) == 1 {
+		return transcript, nil
+	}
+
+	found := make([]string, 0, len(tracks))
+	for _, track := range tracks {
+		if strings.EqualFold(track.Lang, lang) {
+			return track, nil
+		}
+		found = append(found, track.Lang)
+	}
+	return nil, fmt.Errorf("no %q track in subtitles (found: %s)", lang, strings.Join(found, ", "))
+}
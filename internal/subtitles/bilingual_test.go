@@ -0,0 +1,68 @@
+package subtitles
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// TestDetectLineLang tests script detection for single lines.
+func TestDetectLineLang(t *testing.T) {
+	tests := map[string]string{
+		"我们今天讲字幕格式":       "zh",
+		"我用iPhone录的":      "zh",
+		"Today we talk":   "en",
+		"今日はいい天気ですね":      "ja",
+		"안녕하세요":           "ko",
+		"Привет, мир":     "ru",
+		"♪ 123 ♪":         "",
+		"I love 北京 a lot": "en",
+	}
+	for line, want := range tests {
+		if got := DetectLineLang(line); got != want {
+			t.Errorf("DetectLineLang(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+// TestSplitTracks tests that a bilingual SRT is split into one track per language.
+func TestSplitTracks(t *testing.T) {
+	file, err := os.Open("testdata/bilingual.srt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	transcript, err := ParseAll(&SRTSubtitleParser{}, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tracks := SplitTracks(transcript)
+	if len(tracks) != 2 || tracks[0].Lang != "zh" || tracks[1].Lang != "en" {
+		t.Fatalf("got %d tracks: %+v", len(tracks), tracks)
+	}
+	var zh, en []string
+	for _, cue := range tracks[0].Cues {
+		zh = append(zh, cue.Text)
+	}
+	for _, cue := range tracks[1].Cues {
+		en = append(en, cue.Text)
+	}
+	if want := []string{"我们今天讲字幕格式", "♪\n♪", "我用iPhone录的", "谢谢观看"}; !reflect.DeepEqual(zh, want) {
+		t.Errorf("zh track = %q, want %q", zh, want)
+	}
+	if want := []string{"Today we talk about subtitle formats", "♪\n♪", "I recorded it on my iPhone", "Thanks for watching"}; !reflect.DeepEqual(en, want) {
+		t.Errorf("en track = %q, want %q", en, want)
+	}
+	if tracks[1].Cues[2].Start != transcript.Cues[2].Start {
+		t.Errorf("track cues should keep their timing")
+	}
+
+	if _, err := SelectTrack(transcript, "ja"); err == nil {
+		t.Error("expected error for missing track")
+	}
+	mono := &Transcript{Cues: []Cue{{Text: "只有中文"}, {Text: "还是中文\nOK"}, {Text: "第三句"}, {Text: "第四句"}, {Text: "第五句"}, {Text: "第六句"}}}
+	if got, _ := SelectTrack(mono, "en"); got != mono {
+		t.Error("monolingual subtitles should be returned unchanged")
+	}
+}
@@ -0,0 +1,95 @@
+package subtitles
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// NewSubtitleWriter 返回指定格式的写出器，format 为 srt、vtt、ass、bcc、json（旧 JSON 格式）或 txt
+func NewSubtitleWriter(format string) (SubtitleWriter, error) {
+	switch strings.ToLower(format) {
+	case FormatSRT:
+		return &SRTSubtitleWriter{}, nil
+	case FormatVTT:
+		return &VTTSubtitleWriter{}, nil
+	case FormatASS, "ssa":
+		return &ASSSubtitleWriter{}, nil
+	case FormatBCC:
+		return &NewJSONSubtitleWriter{}, nil
+	case FormatOldJSON:
+		return &OldJSONSubtitleWriter{}, nil
+	case FormatTXT:
+		return &TXTSubtitleWriter{}, nil
+	}
+	return nil, fmt.Errorf("unsupported output format %q", format)
+}
+
+// FormatFromPath 根据输出文件的扩展名推断格式；.json 默认写出 BCC 格式
+func FormatFromPath(filePath string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(filePath)); ext {
+	case ".srt":
+		return FormatSRT, nil
+	case ".vtt":
+		return FormatVTT, nil
+	case ".ass", ".ssa":
+		return FormatASS, nil
+	case ".json", ".bcc":
+		return FormatBCC, nil
+	case ".txt":
+		return FormatTXT, nil
+	default:
+		return "", fmt.Errorf("cannot infer output format from extension %q", ext)
+	}
+}
+
+// ConvertFile 解析 src 并以 format 格式写入 dst；format 为空时根据 dst 的扩展名推断。
+// 目标格式能表示的 BCC 显示设置和语言会被保留
+func ConvertFile(src, dst, format string, opts ParseOptions) error {
+	writer, err := writerFor(dst, format)
+	if err != nil {
+		return err
+	}
+
+	transcript, err := ParseSubtitleFileWithOptions(src, opts)
+	if err != nil {
+		return err
+	}
+	return writeSubtitleFile(dst, writer, transcript)
+}
+
+// WriteSubtitleFile 以 format 格式把字幕写入 dst；format 为空时根据 dst 的扩展名推断
+func WriteSubtitleFile(dst, format string, transcript *Transcript) error {
+	writer, err := writerFor(dst, format)
+	if err != nil {
+		return err
+	}
+	return writeSubtitleFile(dst, writer, transcript)
+}
+
+// writerFor 返回写入 dst 所用的写出器，format 为空时根据扩展名推断格式
+func writerFor(dst, format string) (SubtitleWriter, error) {
+	if format == "" {
+		var err error
+		if format, err = FormatFromPath(dst); err != nil {
+			return nil, err
+		}
+	}
+	return NewSubtitleWriter(format)
+}
+
+func writeSubtitleFile(dst string, writer SubtitleWriter, transcript *Transcript) error {
+	file, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	if err := writer.Write(file, transcript); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error closing output file: %v", err)
+	}
+	return nil
+}
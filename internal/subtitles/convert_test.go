@@ -0,0 +1,135 @@
+package subtitles
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const convertSampleBCC = `{"font_size":0.5,"font_color":"#FFEE00","background_alpha":0.3,"background_color":"#000000","Stroke":"none","lang":"zh-CN","version":"v1.6.0.4","body":[` +
+	`{"from":1.2,"to":3.45,"sid":1,"location":2,"content":"第一行\n第二行"},` +
+	`{"from":4,"to":5.5,"sid":2,"location":2,"content":"{花括号}","music":0.9}]}`
+
+// TestConvertPreservesBCCMeta tests that BCC display settings and language survive a round trip through ASS and WebVTT.
+func TestConvertPreservesBCCMeta(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "source.json")
+	if err := os.WriteFile(src, []byte(convertSampleBCC), 0644); err != nil {
+		t.Fatal(err)
+	}
+	original, err := ParseSubtitleFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, via := range []string{"middle.ass", "middle.vtt"} {
+		middle := filepath.Join(dir, via)
+		back := filepath.Join(dir, via+".json")
+		if err := ConvertFile(src, middle, "", ParseOptions{}); err != nil {
+			t.Fatalf("%s: convert returned error: %v", via, err)
+		}
+		if err := ConvertFile(middle, back, "", ParseOptions{}); err != nil {
+			t.Fatalf("%s: convert back returned error: %v", via, err)
+		}
+		result, err := ParseSubtitleFile(back)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Lang != "zh-CN" {
+			t.Errorf("%s: Lang = %q, want zh-CN", via, result.Lang)
+		}
+		want := *original.BCC
+		if via == "middle.vtt" {
+			// WebVTT 无法表示字号
+			want.FontSize = defaultBCCMeta.FontSize
+		}
+		want.Version = ""
+		if !reflect.DeepEqual(*result.BCC, want) {
+			t.Errorf("%s: BCC = %+v, want %+v", via, *result.BCC, want)
+		}
+		if len(result.Cues) != 2 || result.Cues[0].Text != "第一行\n第二行" || result.Cues[1].End != 5500*time.Millisecond {
+			t.Errorf("%s: cues = %+v", via, result.Cues)
+		}
+	}
+}
+
+// TestSRTWriter tests that comments are dropped and cues are renumbered.
+func TestSRTWriter(t *testing.T) {
+	transcript := &Transcript{Cues: []Cue{
+		{Index: 5, Start: 1200 * time.Millisecond, End: 3450 * time.Millisecond, Text: "第一行\n第二行"},
+		{Index: 6, Start: 4 * time.Second, End: 5 * time.Second, Text: "注释", Flags: FlagComment},
+		{Index: 7, Start: time.Hour, End: time.Hour + time.Second, Text: "最后"},
+	}}
+
+	var buf bytes.Buffer
+	if err := (&SRTSubtitleWriter{}).Write(&buf, transcript); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	want := "1\n00:00:01,200 --> 00:00:03,450\n第一行\n第二行\n\n2\n01:00:00,000 --> 01:00:01,000\n最后\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+// TestConvertASSCommentToVTT tests that ASS Comment events are not written as visible WebVTT cues.
+func TestConvertASSCommentToVTT(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "sample.vtt")
+	if err := ConvertFile("testdata/sample.ass", dst, "", ParseOptions{}); err != nil {
+		t.Fatalf("ConvertFile returned error: %v", err)
+	}
+	data, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "校对") {
+		t.Errorf("comment written as a cue:\n%s", data)
+	}
+}
+
+// TestASSWriterRoundTrip tests that written ASS keeps styles, speakers, comments and escaped text.
+func TestASSWriterRoundTrip(t *testing.T) {
+	file, err := os.Open("testdata/sample.ass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	original, err := ParseAll(&ASSSubtitleParser{}, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := (&ASSSubtitleWriter{}).Write(&buf, original); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	reparsed, err := ParseAll(&ASSSubtitleParser{}, strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("reparse returned error: %v", err)
+	}
+
+	if len(reparsed.Styles) != len(original.Styles) {
+		t.Fatalf("got %d styles, want %d", len(reparsed.Styles), len(original.Styles))
+	}
+	for i, style := range original.Styles {
+		got := reparsed.Styles[i]
+		if got.Name != style.Name || got.FontSize != style.FontSize || got.PrimaryColour != style.PrimaryColour {
+			t.Errorf("style %d = %+v, want %+v", i, got, style)
+		}
+	}
+	if len(reparsed.Cues) != len(original.Cues) {
+		t.Fatalf("got %d cues, want %d", len(reparsed.Cues), len(original.Cues))
+	}
+	for i, cue := range original.Cues {
+		got := reparsed.Cues[i]
+		// 定位标签不会写出，只比较文本、时间、样式和说话人
+		if got.Text != cue.Text || got.Start != cue.Start || got.End != cue.End || got.Style != cue.Style ||
+			got.Speaker != cue.Speaker || got.Has(FlagComment) != cue.Has(FlagComment) {
+			t.Errorf("cue %d = %+v, want %+v", i, got, cue)
+		}
+	}
+}
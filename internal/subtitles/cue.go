@@ -0,0 +1,176 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 字幕来源格式
+const (
+	FormatSRT     = "srt"
+	FormatOldJSON = "json"
+	FormatBCC     = "bcc"
+	FormatVTT     = "vtt"
+	FormatASS     = "ass"
+	FormatDanmaku = "danmaku"
+	FormatJSON3   = "json3"
+	FormatTXT     = "txt"
+)
+
+// CueFlag 是字幕条目的附加标记
+type CueFlag uint
+
+const (
+	// FlagUntimed 表示该条目没有可用的时间信息
+	FlagUntimed CueFlag = 1 << iota
+	// FlagComment 表示注释行（ASS 的 Comment 事件），不参与分析
+	FlagComment
+	// FlagSign 表示屏幕文字、特效字等非对白内容
+	FlagSign
+	// FlagKaraoke 表示卡拉 OK / 歌词
+	FlagKaraoke
+)
+
+// LocationBottom 是 BCC JSON 中底部居中的默认位置；
+// location 的取值与小键盘方位一致（1-3 底部，7-9 顶部）
+const LocationBottom = 2
+
+// Cue 表示一条带时间信息的字幕
+type Cue struct {
+	Index    int           // 序号，来自源文件（SRT 序号或 BCC sid）
+	Start    time.Duration // 开始时间
+	End      time.Duration // 结束时间
+	Text     string        // 字幕文本，多行之间以 "\n" 分隔
+	Speaker  string        // 说话人（可选）
+	Music    float64       // 音乐概率（BCC JSON 的 music 字段）
+	Location int           // 屏幕位置（BCC JSON 的 location 字段）
+	Settings string        // 格式相关的显示设置（如 WebVTT 的 cue settings）
+	Style    string        // 样式名（ASS 的 Style 字段）
+	Part     int           // 所属分 P 的序号（由 Merge 设置，从 1 开始；0 表示未合并）
+	Flags    CueFlag       // 附加标记
+}
+
+// Has 判断条目是否带有指定标记
+func (c Cue) Has(flag CueFlag) bool {
+	return c.Flags&flag != 0
+}
+
+// Positional 判断条目是否显示在默认位置以外，这类字幕通常是屏幕文字而不是对白
+func (c Cue) Positional() bool {
+	return c.Location != 0 && c.Location != LocationBottom
+}
+
+// Duration 返回条目的持续时间
+func (c Cue) Duration() time.Duration {
+	return c.End - c.Start
+}
+
+// Transcript 是所有字幕解析器统一产出的结构
+type Transcript struct {
+	Format string // 来源格式，见 Format* 常量
+	Lang   string // 语言（若源文件提供）
+	Cues   []Cue
+	Styles []ASSStyle // ASS 样式定义（仅 ASS/SSA 来源）
+	BCC    *BCCMeta   // BCC JSON 的显示设置（来源为 BCC 或可以从样式推断时）
+	Parts  []Part     // 分 P 边界（由 Merge 设置）
+
+	Warnings []Warning // 解析过程中发现的问题
+}
+
+// BCCMeta 是 BCC JSON 的文件级显示设置
+type BCCMeta struct {
+	FontSize        float64 // 字号，相对于播放器的比例
+	FontColor       string  // 文字颜色，如 "#FFFFFF"
+	BackgroundAlpha float64 // 背景不透明度，0 到 1
+	BackgroundColor string  // 背景颜色，如 "#9C27B0"
+	Stroke          string
+	Type            string
+	Version         string
+}
+
+// Warning 是解析过程中发现的、不影响继续解析的问题
+type Warning struct {
+	Line    int    // 源文件中的行号（从 1 开始）
+	Message string // 问题描述
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// Duration 返回最后一条字幕的结束时间
+func (t *Transcript) Duration() time.Duration {
+	var end time.Duration
+	for _, cue := range t.Cues {
+		if cue.End > end {
+			end = cue.End
+		}
+	}
+	return end
+}
+
+// Without 返回去掉带有任一指定标记的条目后的副本
+func (t *Transcript) Without(flags CueFlag) *Transcript {
+	filtered := *t
+	filtered.Cues = make([]Cue, 0, len(t.Cues))
+	for _, cue := range t.Cues {
+		if !cue.Has(flags) {
+			filtered.Cues = append(filtered.Cues, cue)
+		}
+	}
+	return &filtered
+}
+
+// Text 将字幕渲染为以逗号分隔的纯文本，供分析和保存使用；注释行会被跳过。
+// 合并的分 P 字幕在每个分 P 开始处另起一行并加上 "【P3 标题】" 形式的标记；
+// 说话人改变时另起一行并以 "姓名：" 开头
+func (t *Transcript) Text() string {
+	var paragraph strings.Builder
+	part, speaker := 0, ""
+	for _, cue := range t.Cues {
+		if cue.Has(FlagComment) {
+			continue
+		}
+		if info, ok := t.PartOf(cue); ok && cue.Part != part {
+			part = cue.Part
+			if paragraph.Len() > 0 {
+				paragraph.WriteString("\n")
+			}
+			paragraph.WriteString("【" + info.Label() + "】\n")
+		}
+		if cue.Speaker != "" && cue.Speaker != speaker {
+			speaker = cue.Speaker
+			if paragraph.Len() > 0 && !strings.HasSuffix(paragraph.String(), "\n") {
+				paragraph.WriteString("\n")
+			}
+			paragraph.WriteString(speaker + "：")
+		}
+		for _, line := range strings.Split(cue.Text, "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			paragraph.WriteString(line + ", ")
+		}
+	}
+	return paragraph.String()
+}
+
+// formatClock 将时间格式化为 "hh:mm:ss<sep>mmm"
+func formatClock(d time.Duration, sep string) string {
+	if d < 0 {
+		d = 0
+	}
+	ms := d.Milliseconds()
+	return fmt.Sprintf("%02d:%02d:%02d%s%03d", ms/3600000, ms/60000%60, ms/1000%60, sep, ms%1000)
+}
+
+// FormatShortClock 将时间格式化为 "mm:ss"，超过一小时时为 "h:mm:ss"，用于提示词和报告中的时间标记
+func FormatShortClock(d time.Duration) string {
+	seconds := int(max(d, 0) / time.Second)
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+	}
+	return fmt.Sprintf("%02d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,224 @@
+package subtitles
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 弹幕模式，对应 p 属性的第二个字段
+const (
+	DanmakuModeScroll   = 1 // 普通滚动弹幕（2、3 同样为滚动）
+	DanmakuModeBottom   = 4 // 底部弹幕
+	DanmakuModeTop      = 5 // 顶部弹幕
+	DanmakuModeReverse  = 6 // 逆向弹幕
+	DanmakuModeAdvanced = 7 // 高级弹幕，文本为 JSON
+	DanmakuModeCode     = 8 // 代码弹幕
+)
+
+// danmakuDuration 是一条弹幕在屏幕上停留的大致时间，用于生成 Cue 的结束时间
+const danmakuDuration = 5 * time.Second
+
+// Danmaku 是一条弹幕
+type Danmaku struct {
+	Time     time.Duration // 在视频中出现的时间
+	Mode     int           // 弹幕模式，见 DanmakuMode* 常量
+	FontSize int           // 字号
+	Color    uint32        // 颜色（0xRRGGBB）
+	SendTime time.Time     // 发送时间
+	Pool     int           // 弹幕池：0 普通，1 字幕，2 特殊
+	UserHash string        // 发送者 UID 的 CRC32 哈希
+	RowID    string        // 弹幕 ID
+	Text     string        // 弹幕内容
+}
+
+// DanmakuParser 实现了 SubtitleParser 接口，把 XML 弹幕转换为 Cue（保持文件中的顺序）
+type DanmakuParser struct{}
+
+type danmakuElement struct {
+	P    string `xml:"p,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Parse 解析 XML 弹幕文件，按文件中的顺序逐条输出。
+// 发送者哈希不作为说话人，否则每位观众都会被当作一个说话人；需要时用 ParseDanmaku 读取
+func (p *DanmakuParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	meta.Format = FormatDanmaku
+	count := 0
+	return decodeDanmaku(r, func(item Danmaku) error {
+		count++
+		return emit(Cue{
+			Index: count,
+			Start: item.Time,
+			End:   item.Time + danmakuDuration,
+			Text:  item.Text,
+		})
+	})
+}
+
+// ParseDanmaku 解析 <i><d p="...">内容</d></i> 格式的弹幕，结果按出现时间排序
+func ParseDanmaku(r io.Reader) ([]Danmaku, error) {
+	var items []Danmaku
+	err := decodeDanmaku(r, func(item Danmaku) error {
+		items = append(items, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Time < items[j].Time })
+	return items, nil
+}
+
+// decodeDanmaku 用 xml.Decoder 逐个读取 <d> 元素，不会把整个文件读入内存
+func decodeDanmaku(r io.Reader, fn func(Danmaku) error) error {
+	decoder := xml.NewDecoder(&controlCharFilter{r: r})
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error decoding danmaku XML: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "d" {
+			continue
+		}
+		var element danmakuElement
+		if err := decoder.DecodeElement(&element, &start); err != nil {
+			return fmt.Errorf("error decoding danmaku XML: %v", err)
+		}
+		item, err := parseDanmakuAttr(element.P)
+		if err != nil {
+			return err
+		}
+		item.Text = strings.TrimSpace(element.Text)
+		if item.Text == "" {
+			continue
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+}
+
+// parseDanmakuAttr 解析 p 属性："时间,模式,字号,颜色,发送时间,弹幕池,用户哈希,弹幕ID[,权重]"
+func parseDanmakuAttr(p string) (Danmaku, error) {
+	fields := strings.Split(p, ",")
+	if len(fields) < 8 {
+		return Danmaku{}, fmt.Errorf("invalid danmaku attribute %q", p)
+	}
+
+	var item Danmaku
+	seconds, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return Danmaku{}, fmt.Errorf("invalid danmaku time %q", fields[0])
+	}
+	item.Time = secondsToDuration(seconds)
+	item.Mode, _ = strconv.Atoi(fields[1])
+	item.FontSize, _ = strconv.Atoi(fields[2])
+	color, _ := strconv.ParseUint(fields[3], 10, 32)
+	item.Color = uint32(color)
+	if sent, err := strconv.ParseInt(fields[4], 10, 64); err == nil {
+		item.SendTime = time.Unix(sent, 0)
+	}
+	item.Pool, _ = strconv.Atoi(fields[5])
+	item.UserHash = fields[6]
+	item.RowID = fields[7]
+	return item, nil
+}
+
+// controlCharFilter 去掉 XML 不允许的控制字符，B 站导出的弹幕中偶尔会出现。
+// 多字节 UTF-8 字符的每个字节都不小于 0x80，因此可以按字节过滤
+type controlCharFilter struct {
+	r io.Reader
+}
+
+func (f *controlCharFilter) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	kept := 0
+	for _, b := range p[:n] {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			continue
+		}
+		p[kept] = b
+		kept++
+	}
+	return kept, err
+}
+
+// RenderWithDanmaku 按时间段把字幕和弹幕交织成分析输入，
+// 每段先列出字幕文本，再列出该段出现最多的弹幕及其次数
+func RenderWithDanmaku(transcript *Transcript, items []Danmaku, section time.Duration, topN int) string {
+	if section <= 0 {
+		section = time.Minute
+	}
+
+	end := transcript.Duration()
+	for _, item := range items {
+		if item.Time > end {
+			end = item.Time
+		}
+	}
+
+	var builder strings.Builder
+	for start := time.Duration(0); start <= end; start += section {
+		sectionEnd := start + section
+
+		var subtitleText strings.Builder
+		for _, cue := range transcript.Cues {
+			if cue.Has(FlagComment) || cue.Start < start || cue.Start >= sectionEnd {
+				continue
+			}
+			subtitleText.WriteString(strings.ReplaceAll(cue.Text, "\n", ", ") + ", ")
+		}
+
+		counts := map[string]int{}
+		total := 0
+		for _, item := range items {
+			if item.Time < start || item.Time >= sectionEnd || item.Mode == DanmakuModeAdvanced || item.Mode == DanmakuModeCode {
+				continue
+			}
+			counts[item.Text]++
+			total++
+		}
+		if subtitleText.Len() == 0 && total == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&builder, "[%s - %s]\n", FormatShortClock(start), FormatShortClock(sectionEnd))
+		fmt.Fprintf(&builder, "字幕：%s\n", subtitleText.String())
+		fmt.Fprintf(&builder, "弹幕（共 %d 条）：%s\n\n", total, topDanmaku(counts, topN))
+	}
+	return builder.String()
+}
+
+// topDanmaku 返回出现次数最多的弹幕，格式为 "内容 ×次数"
+func topDanmaku(counts map[string]int, topN int) string {
+	texts := make([]string, 0, len(counts))
+	for text := range counts {
+		texts = append(texts, text)
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		if counts[texts[i]] != counts[texts[j]] {
+			return counts[texts[i]] > counts[texts[j]]
+		}
+		return texts[i] < texts[j]
+	})
+	if topN > 0 && len(texts) > topN {
+		texts = texts[:topN]
+	}
+
+	parts := make([]string, len(texts))
+	for i, text := range texts {
+		parts[i] = fmt.Sprintf("%s ×%d", text, counts[text])
+	}
+	return strings.Join(parts, "；")
+}
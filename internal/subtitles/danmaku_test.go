@@ -0,0 +1,71 @@
+package subtitles
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleDanmaku = `<?xml version="1.0" encoding="UTF-8"?>
+<i><chatserver>chat.bilibili.com</chatserver><chatid>1</chatid>
+<d p="65.2,1,25,16777215,1700000000,0,a1b2c3d4,1001">前方高能</d>
+<d p="3.5,5,25,16711680,1700000001,0,e5f6a7b8,1002">哈哈哈</d>
+<d p="10,1,25,16777215,1700000002,0,a1b2c3d4,1003">哈哈哈</d>
+<d p="70,7,25,16777215,1700000003,0,a1b2c3d4,1004">[0,0,1,"高级"]</d>
+</i>`
+
+// TestParseDanmaku tests attribute decoding and time ordering.
+func TestParseDanmaku(t *testing.T) {
+	items, err := ParseDanmaku(strings.NewReader(sampleDanmaku))
+	if err != nil {
+		t.Fatalf("ParseDanmaku returned error: %v", err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("got %d items, want 4", len(items))
+	}
+
+	first := items[0]
+	if first.Time != 3500*time.Millisecond || first.Mode != DanmakuModeTop || first.Color != 0xFF0000 ||
+		first.UserHash != "e5f6a7b8" || first.RowID != "1002" || first.SendTime.Unix() != 1700000001 {
+		t.Errorf("unexpected first item: %+v", first)
+	}
+}
+
+// TestDanmakuParserHasNoSpeakers tests that danmaku senders are not treated as speakers.
+func TestDanmakuParserHasNoSpeakers(t *testing.T) {
+	transcript, err := ParseAll(&DanmakuParser{}, strings.NewReader(sampleDanmaku))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(transcript.Cues) == 0 {
+		t.Fatal("no cues")
+	}
+	if speakers := transcript.Speakers(); len(speakers) != 0 {
+		t.Errorf("Speakers() = %q, want none", speakers)
+	}
+	if text := transcript.Text(); strings.Contains(text, "a1b2c3d4") {
+		t.Errorf("Text() contains a sender hash: %q", text)
+	}
+}
+
+// TestRenderWithDanmaku tests that danmaku are grouped per section next to the subtitles.
+func TestRenderWithDanmaku(t *testing.T) {
+	items, err := ParseDanmaku(strings.NewReader(sampleDanmaku))
+	if err != nil {
+		t.Fatal(err)
+	}
+	transcript := &Transcript{Cues: []Cue{
+		{Start: time.Second, End: 2 * time.Second, Text: "开场"},
+		{Start: 61 * time.Second, End: 63 * time.Second, Text: "重点来了"},
+	}}
+
+	got := RenderWithDanmaku(transcript, items, time.Minute, 5)
+	want := "[00:00 - 01:00]\n字幕：开场, \n弹幕（共 2 条）：哈哈哈 ×2\n\n" +
+		"[01:00 - 02:00]\n字幕：重点来了, \n弹幕（共 1 条）：前方高能 ×1\n\n"
+	if got != want {
+		t.Errorf("RenderWithDanmaku() =\n%s\nwant:\n%s", got, want)
+	}
+	if strings.Contains(got, "高级") {
+		t.Errorf("advanced danmaku should be skipped")
+	}
+}
@@ -0,0 +1,223 @@
+package subtitles
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// sniffSize 是格式探测时读取的文件头长度
+const sniffSize = 8 << 10
+
+const (
+	// minConfidence 是选中某个格式所需的最低分数
+	minConfidence = 0.3
+	// extensionBonus 是扩展名匹配时额外加的分数，只用于打破平局或辅助弱信号
+	extensionBonus = 0.25
+)
+
+// Detector 是能根据文件开头内容给出置信度的字幕解析器
+type Detector interface {
+	SubtitleParser
+	// Detect 返回 0 到 1 之间的置信度，head 最多为 sniffSize 字节，可能在任意位置被截断
+	Detect(head []byte) float64
+}
+
+type registeredFormat struct {
+	name       string
+	extensions []string
+	factory    func() Detector
+}
+
+var registry []registeredFormat
+
+// RegisterFormat 注册一种字幕格式；先注册的格式在分数相同时优先
+func RegisterFormat(name string, factory func() Detector, extensions ...string) {
+	registry = append(registry, registeredFormat{name: name, extensions: extensions, factory: factory})
+}
+
+func init() {
+	RegisterFormat(FormatVTT, func() Detector { return &VTTSubtitleParser{} }, ".vtt")
+	RegisterFormat(FormatASS, func() Detector { return &ASSSubtitleParser{} }, ".ass", ".ssa")
+	RegisterFormat(FormatDanmaku, func() Detector { return &DanmakuParser{} }, ".xml")
+	RegisterFormat(FormatJSON3, func() Detector { return &JSON3SubtitleParser{} }, ".json3", ".json")
+	RegisterFormat(FormatBCC, func() Detector { return &NewJSONSubtitleParser{} }, ".json", ".bcc")
+	RegisterFormat(FormatOldJSON, func() Detector { return &OldJSONSubtitleParser{} }, ".json")
+	RegisterFormat(FormatSRT, func() Detector { return &SRTSubtitleParser{} }, ".srt")
+	RegisterFormat(FormatTXT, func() Detector { return &TXTSubtitleParser{} }, ".txt")
+}
+
+// Candidate 是一次格式探测中某个格式的得分
+type Candidate struct {
+	Format string
+	Score  float64
+}
+
+// UnsupportedFormatError 表示没有任何已注册的格式能识别该文件
+type UnsupportedFormatError struct {
+	Path       string
+	Candidates []Candidate
+}
+
+func (e *UnsupportedFormatError) Error() string {
+	tried := make([]string, len(e.Candidates))
+	for i, c := range e.Candidates {
+		tried[i] = fmt.Sprintf("%s=%.2f", c.Format, c.Score)
+	}
+	return fmt.Sprintf("unsupported subtitle format in file '%s' (tried %s)", e.Path, strings.Join(tried, ", "))
+}
+
+// DetectFormat 根据文件开头的内容选择解析器，扩展名只作为辅助信号
+func DetectFormat(filePath string, head []byte) (Detector, string, error) {
+	head = bytes.TrimPrefix(head, utf8BOM)
+	ext := strings.ToLower(filepath.Ext(filePath))
+
+	candidates := make([]Candidate, 0, len(registry))
+	best, bestScore := -1, 0.0
+	for i, format := range registry {
+		score := format.factory().Detect(head)
+		if score > 0 {
+			for _, e := range format.extensions {
+				if e == ext {
+					score += extensionBonus
+					break
+				}
+			}
+		}
+		candidates = append(candidates, Candidate{Format: format.name, Score: score})
+		if score >= minConfidence && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if best < 0 {
+		sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+		return nil, "", &UnsupportedFormatError{Path: filePath, Candidates: candidates}
+	}
+	return registry[best].factory(), registry[best].name, nil
+}
+
+var srtTimecodeLine = regexp.MustCompile(`(?m)^\s*\d+:\d+:\d+[,.]\d+\s*-->\s*\d+:\d+:\d+[,.]\d+`)
+
+// Detect 识别 SRT 时间码；没有时间码的纯文本给出很低的分数，需要 .srt 扩展名才会被选中
+func (p *SRTSubtitleParser) Detect(head []byte) float64 {
+	if isVTTHeader(firstLine(head)) {
+		return 0
+	}
+	if srtTimecodeLine.Match(head) {
+		return 0.9
+	}
+	if looksLikeText(head) && !looksLikeMarkup(head) {
+		return 0.1
+	}
+	return 0
+}
+
+// Detect 识别以时间戳开头的转写稿；没有时间戳的纯文本给出很低的分数，需要 .txt 扩展名才会被选中
+func (p *TXTSubtitleParser) Detect(head []byte) float64 {
+	if isVTTHeader(firstLine(head)) || srtTimecodeLine.Match(head) || !looksLikeText(head) {
+		return 0
+	}
+	timed, total := txtTimedLines(head)
+	switch {
+	case timed > 0 && timed*4 >= total:
+		// 时间戳和说话人单独一行时，文本占了大部分行
+		return 0.8
+	case looksLikeMarkup(head):
+		return 0
+	}
+	return 0.1
+}
+
+// Detect 识别 "WEBVTT" 文件头
+func (p *VTTSubtitleParser) Detect(head []byte) float64 {
+	if isVTTHeader(firstLine(head)) {
+		return 1
+	}
+	return 0
+}
+
+// Detect 识别 [Script Info] 或 [Events] 段落
+func (p *ASSSubtitleParser) Detect(head []byte) float64 {
+	lower := bytes.ToLower(head)
+	switch {
+	case bytes.Contains(lower, []byte("[script info]")):
+		return 0.95
+	case bytes.Contains(lower, []byte("[events]")) && bytes.Contains(head, []byte("Dialogue:")):
+		return 0.8
+	}
+	return 0
+}
+
+// Detect 识别 <d p="..."> 弹幕元素
+func (p *DanmakuParser) Detect(head []byte) float64 {
+	if bytes.Contains(head, []byte(`<d p="`)) {
+		return 0.95
+	}
+	if bytes.Contains(head, []byte("<i>")) && bytes.Contains(head, []byte("<chatid>")) {
+		return 0.8
+	}
+	return 0
+}
+
+// Detect 识别以数组开头、条目包含 from/content 的旧 JSON 格式
+func (p *OldJSONSubtitleParser) Detect(head []byte) float64 {
+	if jsonStart(head) != '[' {
+		return 0
+	}
+	if bytes.Contains(head, []byte(`"content"`)) && bytes.Contains(head, []byte(`"from"`)) {
+		return 0.9
+	}
+	if bytes.Equal(bytes.TrimSpace(head), []byte("[]")) {
+		return 0.5
+	}
+	return 0
+}
+
+// Detect 识别带有 body 数组的 BCC JSON 格式
+func (p *NewJSONSubtitleParser) Detect(head []byte) float64 {
+	if jsonStart(head) != '{' || !bytes.Contains(head, []byte(`"body"`)) {
+		return 0
+	}
+	if bytes.Contains(head, []byte(`"content"`)) || bytes.Contains(head, []byte(`"font_size"`)) {
+		return 0.9
+	}
+	return 0.6
+}
+
+// firstLine 返回内容的第一行
+func firstLine(head []byte) string {
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+	return strings.TrimRight(string(head), "\r \t")
+}
+
+// jsonStart 返回第一个非空白字符
+func jsonStart(head []byte) byte {
+	trimmed := bytes.TrimLeft(head, " \t\r\n")
+	if len(trimmed) == 0 {
+		return 0
+	}
+	return trimmed[0]
+}
+
+// looksLikeText 判断内容是否为文本（允许末尾被截断的 UTF-8 字符）
+func looksLikeText(head []byte) bool {
+	if len(head) == 0 || bytes.IndexByte(head, 0) >= 0 {
+		return false
+	}
+	return validUTF8(head)
+}
+
+// looksLikeMarkup 判断内容是否为 JSON 或 XML，避免把它们当作纯文本
+func looksLikeMarkup(head []byte) bool {
+	switch jsonStart(head) {
+	case '{', '[', '<':
+		return true
+	}
+	return false
+}
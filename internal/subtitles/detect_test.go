@@ -0,0 +1,51 @@
+package subtitles
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestDetectFormat tests that content wins over misleading or missing extensions.
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		path string
+		data string
+		want string
+	}{
+		{"lecture.txt", "1\n00:00:01,000 --> 00:00:02,000\n你好\n", FormatSRT},
+		{"lecture", "1\n0:0:1,0 --> 0:0:2,0\n你好\n", FormatSRT},
+		{"captions.srt", "WEBVTT\n\n00:01.000 --> 00:02.000\nhi\n", FormatVTT},
+		{"youtube.json", `{"wireMagic":"pb3","events":[{"tStartMs":0,"dDurationMs":1000,"segs":[{"utf8":"hi"}]}]}`, FormatJSON3},
+		{"bilibili.json", `{"font_size":0.4,"font_color":"#FFFFFF","body":[{"from":0,"to":1,"content":"你好"}]}`, FormatBCC},
+		{"old.json", `[{"from":0,"to":1,"sid":1,"content":"你好"}]`, FormatOldJSON},
+		{"danmaku.txt", `<?xml version="1.0"?><i><d p="1,1,25,0,0,0,abc,1">hi</d></i>`, FormatDanmaku},
+		{"fansub.txt", "[Script Info]\nScriptType: v4.00+\n", FormatASS},
+		{"notes.txt", "没有时间码的纯文本\n", FormatTXT},
+		{"meeting.txt", "[00:00:05] 主持人：大家好\n[00:00:12] 张三：你好\n", FormatTXT},
+		{"meeting", "说话人 1 00:05\n大家好\n\n说话人 2 00:12\n你好\n", FormatTXT},
+		{"untimed.srt", "没有时间码的纯文本\n", FormatSRT},
+	}
+	for _, tt := range tests {
+		_, got, err := DetectFormat(tt.path, []byte(tt.data))
+		if err != nil {
+			t.Errorf("%s: DetectFormat returned error: %v", tt.path, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: detected %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestDetectFormatUnsupported tests that the error lists every candidate that was tried.
+func TestDetectFormatUnsupported(t *testing.T) {
+	_, _, err := DetectFormat("notes", []byte("plain prose without any timing"))
+
+	var unsupported *UnsupportedFormatError
+	if !errors.As(err, &unsupported) {
+		t.Fatalf("expected UnsupportedFormatError, got %v", err)
+	}
+	if len(unsupported.Candidates) != len(registry) {
+		t.Errorf("got %d candidates, want %d", len(unsupported.Candidates), len(registry))
+	}
+}
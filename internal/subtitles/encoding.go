@@ -0,0 +1,169 @@
+package subtitles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+	"io"
+	"unicode/utf8"
+)
+
+// 自动检测能识别的字符编码
+const (
+	EncodingUTF8    = "utf-8"
+	EncodingUTF16LE = "utf-16le"
+	EncodingUTF16BE = "utf-16be"
+	EncodingGB18030 = "gb18030"
+	EncodingBig5    = "big5"
+)
+
+// DecodeText 把字幕文件内容转换为不带 BOM 的 UTF-8，见 NewDecodingReader。
+// 返回转换后的内容、实际使用的编码名以及转换后残留的无效字符数。
+func DecodeText(data []byte, name string) ([]byte, string, int, error) {
+	r, name, err := NewDecodingReader(bytes.NewReader(data), name)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("error decoding %s text: %v", name, err)
+	}
+	return decoded, name, countInvalid(decoded), nil
+}
+
+// NewDecodingReader 返回把 r 的内容转换为不带 BOM 的 UTF-8 的 Reader，以及使用的编码名。
+// name 不为空时强制使用该编码（支持 WHATWG 编码名，如 gbk、big5、utf-16le），
+// 否则根据开头 sniffSize 字节中的 BOM、UTF-8 有效性和双字节分布猜测编码。
+func NewDecodingReader(r io.Reader, name string) (io.Reader, string, error) {
+	buffered := bufio.NewReaderSize(r, sniffSize)
+
+	var enc encoding.Encoding
+	if name != "" {
+		var err error
+		if enc, err = htmlindex.Get(name); err != nil {
+			return nil, "", fmt.Errorf("unknown encoding %q: %v", name, err)
+		}
+		if canonical, err := htmlindex.Name(enc); err == nil {
+			name = canonical
+		}
+	} else {
+		sample, err := buffered.Peek(sniffSize)
+		if err != nil && err != io.EOF {
+			return nil, "", err
+		}
+		name, enc = detectEncoding(sample)
+	}
+
+	if name == EncodingUTF8 {
+		return skipBOM(buffered), name, nil
+	}
+	return skipBOM(transform.NewReader(buffered, enc.NewDecoder())), name, nil
+}
+
+// skipBOM 跳过开头的 UTF-8 字节序标记
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if head, err := buffered.Peek(len(utf8BOM)); err == nil && bytes.Equal(head, utf8BOM) {
+		buffered.Discard(len(utf8BOM))
+	}
+	return buffered
+}
+
+// detectEncoding 猜测内容的字符编码
+func detectEncoding(data []byte) (string, encoding.Encoding) {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return EncodingUTF8, unicode.UTF8
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		return EncodingUTF16LE, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		return EncodingUTF16BE, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)
+	}
+
+	sample := data
+	if len(sample) > sniffSize {
+		sample = sample[:sniffSize]
+	}
+
+	// 没有 BOM 的 UTF-16：ASCII 字符的高字节为 0
+	evenZeros, oddZeros := 0, 0
+	for i, b := range sample {
+		if b == 0 {
+			if i%2 == 0 {
+				evenZeros++
+			} else {
+				oddZeros++
+			}
+		}
+	}
+	if oddZeros > len(sample)/4 && evenZeros < oddZeros/4 {
+		return EncodingUTF16LE, unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	}
+	if evenZeros > len(sample)/4 && oddZeros < evenZeros/4 {
+		return EncodingUTF16BE, unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+
+	if validUTF8(sample) {
+		return EncodingUTF8, unicode.UTF8
+	}
+	if looksLikeBig5(sample) {
+		return EncodingBig5, traditionalchinese.Big5
+	}
+	return EncodingGB18030, simplifiedchinese.GB18030
+}
+
+// looksLikeBig5 根据双字节字符的尾字节分布区分 Big5 与 GBK/GB18030。
+// GB2312 常用汉字的尾字节都不小于 0xA1，而 Big5 常用字约有四成尾字节落在 0x40-0x7E。
+func looksLikeBig5(data []byte) bool {
+	pairs, lowTrail := 0, 0
+	for i := 0; i+1 < len(data); i++ {
+		lead := data[i]
+		if lead < 0x81 || lead == 0xFF {
+			continue
+		}
+		trail := data[i+1]
+		// GB18030 四字节序列的第二个字节是数字，Big5 中不会出现
+		if trail >= 0x30 && trail <= 0x39 {
+			return false
+		}
+		pairs++
+		if trail >= 0x40 && trail <= 0x7E {
+			lowTrail++
+		}
+		i++
+	}
+	return pairs > 0 && lowTrail*10 > pairs*2
+}
+
+// validUTF8 判断内容是否为有效的 UTF-8，允许末尾有一个被截断的字符
+func validUTF8(data []byte) bool {
+	for i := 0; i < utf8.UTFMax; i++ {
+		if utf8.Valid(data) {
+			return true
+		}
+		if len(data) == 0 {
+			break
+		}
+		data = data[:len(data)-1]
+	}
+	return false
+}
+
+// countInvalid 统计无效的 UTF-8 序列和替换字符 U+FFFD 的数量
+func countInvalid(data []byte) int {
+	count := 0
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError {
+			count++
+		}
+		data = data[size:]
+	}
+	return count
+}
@@ -0,0 +1,66 @@
+package subtitles
+
+import (
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"testing"
+)
+
+const encodingSample = "1\n00:00:01,000 --> 00:00:02,000\n平常打混双的都知道，最怕就是女后男前\n"
+
+// TestDecodeTextDetection tests BOM handling and automatic detection of common Chinese encodings.
+func TestDecodeTextDetection(t *testing.T) {
+	traditional := "1\n00:00:01,000 --> 00:00:02,000\n這是一個關於臺灣電影的節目，我們會討論導演與演員\n"
+
+	tests := []struct {
+		name string
+		enc  encoding.Encoding
+		text string
+		want string
+	}{
+		{"utf-8 bom", unicode.UTF8BOM, encodingSample, EncodingUTF8},
+		{"utf-16le bom", unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), encodingSample, EncodingUTF16LE},
+		{"utf-16be bom", unicode.UTF16(unicode.BigEndian, unicode.UseBOM), encodingSample, EncodingUTF16BE},
+		{"utf-16le no bom", unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), encodingSample, EncodingUTF16LE},
+		{"gbk", simplifiedchinese.GBK, encodingSample, EncodingGB18030},
+		{"big5", traditionalchinese.Big5, traditional, EncodingBig5},
+	}
+	for _, tt := range tests {
+		data, err := tt.enc.NewEncoder().Bytes([]byte(tt.text))
+		if err != nil {
+			t.Fatalf("%s: encode: %v", tt.name, err)
+		}
+		decoded, name, invalid, err := DecodeText(data, "")
+		if err != nil {
+			t.Errorf("%s: DecodeText returned error: %v", tt.name, err)
+			continue
+		}
+		if name != tt.want || string(decoded) != tt.text || invalid != 0 {
+			t.Errorf("%s: got (%q, %s, %d), want (%q, %s, 0)", tt.name, decoded, name, invalid, tt.text, tt.want)
+		}
+	}
+}
+
+// TestDecodeTextOverride tests the explicit encoding override and the invalid character count.
+func TestDecodeTextOverride(t *testing.T) {
+	data, err := simplifiedchinese.GBK.NewEncoder().Bytes([]byte(encodingSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decoded, name, _, err := DecodeText(data, "GBK")
+	if err != nil || name != "gbk" || string(decoded) != encodingSample {
+		t.Errorf("override gbk: got (%q, %s, %v)", decoded, name, err)
+	}
+
+	_, _, invalid, err := DecodeText(data, "utf-8")
+	if err != nil || invalid == 0 {
+		t.Errorf("forcing utf-8 on GBK data should report invalid characters, got %d (%v)", invalid, err)
+	}
+
+	if _, _, _, err := DecodeText(data, "no-such-encoding"); err == nil {
+		t.Errorf("expected error for unknown encoding")
+	}
+}
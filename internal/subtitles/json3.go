@@ -0,0 +1,94 @@
+package subtitles
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// JSON3SubtitleParser 实现了 SubtitleParser 接口，处理 YouTube 的 json3 字幕格式
+type JSON3SubtitleParser struct{}
+
+type json3Event struct {
+	TStartMs    int64 `json:"tStartMs"`
+	DDurationMs int64 `json:"dDurationMs"`
+	Segs        []struct {
+		UTF8 string `json:"utf8"`
+	} `json:"segs"`
+}
+
+// Parse 解析 json3 格式字幕文件，events 中的事件逐条解码
+func (p *JSON3SubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	meta.Format = FormatJSON3
+	decoder := json.NewDecoder(r)
+
+	if err := expectJSONDelim(decoder, '{'); err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+	count := 0
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+		if key != "events" {
+			var skipped json.RawMessage
+			if err := decoder.Decode(&skipped); err != nil {
+				return fmt.Errorf("error decoding JSON: %v", err)
+			}
+			continue
+		}
+
+		isArray, err := enterJSONArray(decoder)
+		if err != nil {
+			return fmt.Errorf("error decoding JSON events: %v", err)
+		}
+		for isArray && decoder.More() {
+			var event json3Event
+			if err := decoder.Decode(&event); err != nil {
+				return fmt.Errorf("error decoding JSON event: %v", err)
+			}
+
+			// 没有 segs 的事件只是窗口定义
+			var text strings.Builder
+			for _, seg := range event.Segs {
+				text.WriteString(seg.UTF8)
+			}
+			content := strings.TrimSpace(text.String())
+			if content == "" {
+				continue
+			}
+			count++
+			start := time.Duration(event.TStartMs) * time.Millisecond
+			err := emit(Cue{
+				Index: count,
+				Start: start,
+				End:   start + time.Duration(event.DDurationMs)*time.Millisecond,
+				Text:  content,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if isArray {
+			if err := expectJSONDelim(decoder, ']'); err != nil {
+				return fmt.Errorf("error decoding JSON events: %v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Detect 识别带有 events/tStartMs 的 json3 格式
+func (p *JSON3SubtitleParser) Detect(head []byte) float64 {
+	if jsonStart(head) != '{' || !bytes.Contains(head, []byte(`"events"`)) {
+		return 0
+	}
+	if bytes.Contains(head, []byte(`"tStartMs"`)) || bytes.Contains(head, []byte(`"wireMagic"`)) {
+		return 0.95
+	}
+	return 0.4
+}
@@ -0,0 +1,193 @@
+package subtitles
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 检查结果的严重程度
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// 检查规则的名称
+const (
+	RuleParse      = "parse"       // 解析器发现的问题
+	RuleEmpty      = "empty"       // 空条目
+	RuleDuration   = "duration"    // 持续时间为零或负数
+	RuleOverlap    = "overlap"     // 与上一条重叠
+	RuleGap        = "gap"         // 与上一条的间隔过短
+	RuleOrder      = "order"       // 序号重复或倒退，或开始时间早于上一条
+	RuleCPS        = "cps"         // 每秒字数过多
+	RuleLineLength = "line-length" // 单行过长
+)
+
+// LintOptions 是字幕检查的阈值
+type LintOptions struct {
+	MaxCJKCPS    float64       // 中日韩文字每秒最多字数
+	MaxLatinCPS  float64       // 拉丁字母文字每秒最多字符数
+	MaxCJKLine   int           // 中日韩文字每行最多字数
+	MaxLatinLine int           // 拉丁字母文字每行最多字符数
+	MinGap       time.Duration // 相邻两条之间的最短间隔（不相连时）
+	Ignore       []string      // 不检查的规则
+}
+
+// DefaultLintOptions 参考常见流媒体平台的字幕规范
+var DefaultLintOptions = LintOptions{
+	MaxCJKCPS:    9,
+	MaxLatinCPS:  20,
+	MaxCJKLine:   16,
+	MaxLatinLine: 42,
+	MinGap:       80 * time.Millisecond,
+}
+
+// LintIssue 是检查发现的一个问题
+type LintIssue struct {
+	Cue      int    `json:"cue,omitempty"`  // 条目序号，解析问题为 0
+	Line     int    `json:"line,omitempty"` // 源文件行号（仅解析问题）
+	Start    string `json:"start,omitempty"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	location := fmt.Sprintf("line %d", i.Line)
+	if i.Cue != 0 {
+		location = fmt.Sprintf("cue %d", i.Cue)
+		if i.Start != "" {
+			location += " (" + i.Start + ")"
+		}
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", location, i.Severity, i.Message, i.Rule)
+}
+
+// Lint 检查字幕中的常见质量问题，解析警告也作为问题返回。注释行不参与检查
+func Lint(transcript *Transcript, opts LintOptions) []LintIssue {
+	ignored := map[string]bool{}
+	for _, rule := range opts.Ignore {
+		ignored[strings.TrimSpace(rule)] = true
+	}
+
+	var issues []LintIssue
+	if !ignored[RuleParse] {
+		for _, warning := range transcript.Warnings {
+			issues = append(issues, LintIssue{Line: warning.Line, Rule: RuleParse, Severity: SeverityWarning, Message: warning.Message})
+		}
+	}
+
+	var prev *Cue
+	for i := range transcript.Cues {
+		cue := &transcript.Cues[i]
+		if cue.Has(FlagComment) {
+			continue
+		}
+		report := func(rule, severity, format string, args ...interface{}) {
+			if ignored[rule] {
+				return
+			}
+			issue := LintIssue{Cue: cue.Index, Rule: rule, Severity: severity, Message: fmt.Sprintf(format, args...)}
+			if !cue.Has(FlagUntimed) {
+				issue.Start = formatClock(cue.Start, ",")
+			}
+			issues = append(issues, issue)
+		}
+
+		if prev != nil && cue.Index != 0 && cue.Index <= prev.Index {
+			report(RuleOrder, SeverityWarning, "cue number %d after %d", cue.Index, prev.Index)
+		}
+		if strings.TrimSpace(cue.Text) == "" {
+			report(RuleEmpty, SeverityError, "cue has no text")
+		}
+
+		if !cue.Has(FlagUntimed) {
+			if cue.Duration() <= 0 {
+				report(RuleDuration, SeverityError, "duration is %v", cue.Duration())
+			} else if cps, limit := readingSpeed(cue.Text, cue.Duration(), opts); limit > 0 && cps > limit {
+				report(RuleCPS, SeverityWarning, "%.1f characters per second (max %g)", cps, limit)
+			}
+
+			if prev != nil && !prev.Has(FlagUntimed) {
+				switch gap := cue.Start - prev.End; {
+				case cue.Start < prev.Start:
+					report(RuleOrder, SeverityWarning, "starts before the previous cue (%s)", formatClock(prev.Start, ","))
+				case gap < 0:
+					report(RuleOverlap, SeverityError, "overlaps the previous cue by %v", -gap)
+				case gap > 0 && gap < opts.MinGap:
+					report(RuleGap, SeverityWarning, "gap of %v after the previous cue (min %v)", gap, opts.MinGap)
+				}
+			}
+		}
+
+		for n, line := range strings.Split(cue.Text, "\n") {
+			length := utf8.RuneCountInString(strings.TrimSpace(line))
+			limit := opts.MaxLatinLine
+			if isCJKLine(line) {
+				limit = opts.MaxCJKLine
+			}
+			if limit > 0 && length > limit {
+				report(RuleLineLength, SeverityWarning, "line %d has %d characters (max %d)", n+1, length, limit)
+			}
+		}
+		prev = cue
+	}
+	return issues
+}
+
+// HasErrors 判断问题中是否有错误级别的问题
+func HasErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// readingSpeed 返回条目的每秒字数以及适用的上限；空白和标点不计入字数。
+// 双语条目中的中日韩文字和拉丁字母分别计算，返回超出比例较大的一种
+func readingSpeed(text string, duration time.Duration, opts LintOptions) (float64, float64) {
+	var cjkChars, latinChars int
+	for _, line := range strings.Split(text, "\n") {
+		if isCJKLine(line) {
+			cjkChars += textLength(line)
+		} else {
+			latinChars += textLength(line)
+		}
+	}
+
+	var cps, limit float64
+	for _, speed := range [][2]float64{
+		{float64(cjkChars) / duration.Seconds(), opts.MaxCJKCPS},
+		{float64(latinChars) / duration.Seconds(), opts.MaxLatinCPS},
+	} {
+		if speed[1] > 0 && speed[0] > 0 && (limit == 0 || speed[0]/speed[1] > cps/limit) {
+			cps, limit = speed[0], speed[1]
+		}
+	}
+	return cps, limit
+}
+
+// textLength 返回一行中除空白和标点以外的字数
+func textLength(line string) int {
+	n := 0
+	for _, r := range line {
+		if !unicode.IsSpace(r) && !unicode.IsPunct(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// isCJKLine 判断一行是否为中日韩文字
+func isCJKLine(line string) bool {
+	switch DetectLineLang(line) {
+	case "zh", "ja", "ko":
+		return true
+	}
+	return false
+}
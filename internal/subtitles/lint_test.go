@@ -0,0 +1,50 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLint tests that each rule is reported on the offending cue.
+func TestLint(t *testing.T) {
+	ms := time.Millisecond
+	transcript := &Transcript{
+		Warnings: []Warning{{Line: 4, Message: "cue 2 has no text"}},
+		Cues: []Cue{
+			{Index: 1, Start: 0, End: 2000 * ms, Text: "正常的一句"},
+			{Index: 2, Start: 2030 * ms, End: 3000 * ms, Text: "间隔太短"},
+			{Index: 3, Start: 2900 * ms, End: 4000 * ms, Text: "和上一条重叠"},
+			{Index: 3, Start: 5000 * ms, End: 5000 * ms, Text: "序号重复而且没有时长"},
+			{Index: 5, Start: 6000 * ms, End: 7000 * ms, Text: "这一条字幕在一秒钟之内显示了太多的字"},
+			{Index: 6, Start: 8000 * ms, End: 10000 * ms, Text: " "},
+			{Index: 7, Start: 11000 * ms, End: 14000 * ms, Text: "双语字幕\nThis English line is fine to read"},
+		},
+	}
+
+	var got []string
+	for _, issue := range Lint(transcript, DefaultLintOptions) {
+		got = append(got, issue.Rule+"@"+strings.Fields(issue.String())[1])
+	}
+	want := []string{
+		"parse@4:",
+		"gap@2",
+		"overlap@3",
+		"order@3",
+		"duration@3",
+		"cps@5",
+		"line-length@5",
+		"empty@6",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+
+	opts := DefaultLintOptions
+	opts.Ignore = []string{RuleParse, RuleCPS, RuleLineLength, RuleGap, RuleOrder}
+	issues := Lint(transcript, opts)
+	if len(issues) != 3 || !HasErrors(issues) {
+		t.Errorf("with ignored rules: %v", issues)
+	}
+}
@@ -0,0 +1,90 @@
+package subtitles
+
+import (
+	"fmt"
+	"time"
+)
+
+// Part 是合并后字幕中的一个分 P
+type Part struct {
+	Index  int           // 分 P 序号，从 1 开始
+	Title  string        // 分 P 标题，可以为空
+	Offset time.Duration // 该分 P 在合并后时间轴上的起点
+}
+
+// Label 返回分 P 的标记，如 "P3" 或 "P3 标题"
+func (p Part) Label() string {
+	if p.Title == "" {
+		return fmt.Sprintf("P%d", p.Index)
+	}
+	return fmt.Sprintf("P%d %s", p.Index, p.Title)
+}
+
+// MergeOptions 是合并分 P 字幕的参数
+type MergeOptions struct {
+	Titles  []string        // 各分 P 的标题，可以为空
+	Offsets []time.Duration // 各分 P 的起始时间；为空时按前面各 P 的累计时长依次排列
+	Gap     time.Duration   // 按累计时长排列时，相邻两 P 之间留出的间隔
+}
+
+// Merge 按顺序合并多个分 P 的字幕：每个分 P 的时间整体平移到它在合并时间轴上的起点，
+// 条目重新编号并记录所属分 P，Transcript.Parts 保留分 P 的边界。
+// 语言、样式和 BCC 显示设置取自第一个提供它们的分 P，警告会加上分 P 前缀
+func Merge(parts []*Transcript, opts MergeOptions) (*Transcript, error) {
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("no subtitles to merge")
+	}
+	if len(opts.Offsets) != 0 && len(opts.Offsets) != len(parts) {
+		return nil, fmt.Errorf("got %d offsets for %d parts", len(opts.Offsets), len(parts))
+	}
+	if len(opts.Titles) != 0 && len(opts.Titles) != len(parts) {
+		return nil, fmt.Errorf("got %d titles for %d parts", len(opts.Titles), len(parts))
+	}
+
+	merged := &Transcript{Format: parts[0].Format}
+	var offset time.Duration
+	for i, part := range parts {
+		if len(opts.Offsets) != 0 {
+			offset = opts.Offsets[i]
+		}
+		info := Part{Index: i + 1, Offset: offset}
+		if len(opts.Titles) != 0 {
+			info.Title = opts.Titles[i]
+		}
+		merged.Parts = append(merged.Parts, info)
+
+		if merged.Lang == "" {
+			merged.Lang = part.Lang
+		}
+		if merged.Styles == nil {
+			merged.Styles = part.Styles
+		}
+		if merged.BCC == nil {
+			merged.BCC = part.BCC
+		}
+		for _, warning := range part.Warnings {
+			warning.Message = fmt.Sprintf("P%d: %s", info.Index, warning.Message)
+			merged.Warnings = append(merged.Warnings, warning)
+		}
+
+		for _, cue := range part.Cues {
+			cue.Index = len(merged.Cues) + 1
+			cue.Part = info.Index
+			if !cue.Has(FlagUntimed) {
+				cue.Start += offset
+				cue.End += offset
+			}
+			merged.Cues = append(merged.Cues, cue)
+		}
+		offset += part.Duration() + opts.Gap
+	}
+	return merged, nil
+}
+
+// PartOf 返回条目所属的分 P；字幕不是由多个分 P 合并而来时返回 false
+func (t *Transcript) PartOf(cue Cue) (Part, bool) {
+	if cue.Part < 1 || cue.Part > len(t.Parts) {
+		return Part{}, false
+	}
+	return t.Parts[cue.Part-1], true
+}
@@ -0,0 +1,50 @@
+package subtitles
+
+import (
+	"testing"
+	"time"
+)
+
+// TestMerge tests that parts are shifted by the cumulative duration or explicit offsets.
+func TestMerge(t *testing.T) {
+	p1 := &Transcript{Lang: "zh", Cues: []Cue{
+		{Index: 1, Start: 0, End: 2 * time.Second, Text: "第一P开头"},
+		{Index: 2, Start: 2 * time.Second, End: 10 * time.Second, Text: "第一P结尾"},
+	}}
+	p2 := &Transcript{Cues: []Cue{
+		{Index: 1, Start: time.Second, End: 3 * time.Second, Text: "第二P"},
+	}, Warnings: []Warning{{Line: 3, Message: "cue 1 has no text"}}}
+
+	merged, err := Merge([]*Transcript{p1, p2}, MergeOptions{Titles: []string{"上", "下"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := merged.Cues[2]
+	if last.Index != 3 || last.Part != 2 || last.Start != 11*time.Second || last.End != 13*time.Second {
+		t.Errorf("last cue = %+v", last)
+	}
+	if merged.Lang != "zh" || len(merged.Parts) != 2 || merged.Parts[1].Offset != 10*time.Second {
+		t.Errorf("merged = %+v", merged)
+	}
+	if got := merged.Warnings[0].Message; got != "P2: cue 1 has no text" {
+		t.Errorf("warning = %q", got)
+	}
+	if got, want := merged.Text(), "【P1 上】\n第一P开头, 第一P结尾, \n【P2 下】\n第二P, "; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	if p1.Cues[0].Part != 0 || p2.Cues[0].Start != time.Second {
+		t.Error("Merge modified its input")
+	}
+
+	merged, err = Merge([]*Transcript{p1, p2}, MergeOptions{Offsets: []time.Duration{0, time.Minute}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := merged.Cues[2].Start; got != time.Minute+time.Second {
+		t.Errorf("explicit offset: start = %v", got)
+	}
+
+	if _, err := Merge([]*Transcript{p1, p2}, MergeOptions{Offsets: []time.Duration{0}}); err == nil {
+		t.Error("expected error for mismatched offsets")
+	}
+}
@@ -0,0 +1,287 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Anchor 是重新同步时的一个对应点：字幕中 From 时刻的内容应出现在 To 时刻
+type Anchor struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Shift 把所有时间平移 offset，offset 可以为负
+func Shift(transcript *Transcript, offset time.Duration) *Transcript {
+	return Linear(transcript, 1, offset)
+}
+
+// Scale 把所有时间乘以 factor
+func Scale(transcript *Transcript, factor float64) *Transcript {
+	return Linear(transcript, factor, 0)
+}
+
+// ScaleFrameRate 修正帧率变化造成的时间漂移：按 from 帧率制作的字幕用于 to 帧率的视频
+// （如 23.976 到 25 的 PAL 加速）时，时间乘以 from/to
+func ScaleFrameRate(transcript *Transcript, from, to float64) (*Transcript, error) {
+	if from <= 0 || to <= 0 {
+		return nil, fmt.Errorf("invalid frame rates %g and %g", from, to)
+	}
+	return Scale(transcript, from/to), nil
+}
+
+// Resync 根据两个对应点做线性重新同步，同时修正固定偏移和匀速漂移
+func Resync(transcript *Transcript, a, b Anchor) (*Transcript, error) {
+	scale, offset, err := ResyncLinear(a, b)
+	if err != nil {
+		return nil, err
+	}
+	return Linear(transcript, scale, offset), nil
+}
+
+// ResyncLinear 返回经过两个对应点的线性变换 t*scale+offset
+func ResyncLinear(a, b Anchor) (float64, time.Duration, error) {
+	if a.From == b.From {
+		return 0, 0, fmt.Errorf("anchor points must be at different times")
+	}
+	scale := float64(b.To-a.To) / float64(b.From-a.From)
+	if scale <= 0 {
+		return 0, 0, fmt.Errorf("anchor points are in reverse order")
+	}
+	return scale, a.To - time.Duration(math.Round(float64(a.From)*scale)), nil
+}
+
+// Linear 把每个时间 t 变换为 t*scale+offset 并取整到毫秒，返回新的 Transcript。
+// 变换后开始时间为负的条目从 0 开始，结束时间不晚于 0 的条目被删除；没有时间信息的条目保持不变
+func Linear(transcript *Transcript, scale float64, offset time.Duration) *Transcript {
+	transform := linearTransform(scale, offset)
+
+	result := *transcript
+	result.Cues = make([]Cue, 0, len(transcript.Cues))
+	for _, cue := range transcript.Cues {
+		if !cue.Has(FlagUntimed) {
+			var ok bool
+			if cue.Start, cue.End, ok = retimeSpan(cue.Start, cue.End, transform); !ok {
+				continue
+			}
+		}
+		result.Cues = append(result.Cues, cue)
+	}
+
+	if transcript.Parts != nil {
+		result.Parts = make([]Part, len(transcript.Parts))
+		for i, part := range transcript.Parts {
+			part.Offset = transform(part.Offset)
+			result.Parts[i] = part
+		}
+	}
+	return &result
+}
+
+// linearTransform 返回把时间 t 变换为 t*scale+offset 并取整到毫秒的函数
+func linearTransform(scale float64, offset time.Duration) func(time.Duration) time.Duration {
+	return func(t time.Duration) time.Duration {
+		return (time.Duration(math.Round(float64(t)*scale)) + offset).Round(time.Millisecond)
+	}
+}
+
+// retimeSpan 变换一条字幕的起止时间：开始时间为负时从 0 开始，结束时间不晚于 0 时返回 false 表示删除
+func retimeSpan(start, end time.Duration, transform func(time.Duration) time.Duration) (time.Duration, time.Duration, bool) {
+	start, end = transform(start), transform(end)
+	if end <= 0 {
+		return 0, 0, false
+	}
+	if start < 0 {
+		start = 0
+	}
+	return start, end, true
+}
+
+// RetimeFile 把 src 中的时间变换为 t*scale+offset 后以源格式写入 dst，规则与 Linear 相同。
+// ASS/SSA 和 WebVTT 只改写时间戳，文件头、样式、覆盖标签和定位原样保留；
+// 其他格式解析后重新写出
+func RetimeFile(src, dst string, scale float64, offset time.Duration, opts ParseOptions) error {
+	file, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("error opening subtitle file: %v", err)
+	}
+	defer file.Close()
+	parser, decoded, err := NewSubtitleParser(src, file, opts)
+	if err != nil {
+		return err
+	}
+
+	transform := linearTransform(scale, offset)
+	var rewrite func(w io.Writer) error
+	switch parser.(type) {
+	case *ASSSubtitleParser:
+		rewrite = func(w io.Writer) error { return retimeASS(decoded, w, transform, scale) }
+	case *VTTSubtitleParser:
+		rewrite = func(w io.Writer) error { return retimeVTT(decoded, w, transform) }
+	default:
+		transcript, err := ParseAll(parser, decoded)
+		if err != nil {
+			return fmt.Errorf("error parsing subtitle file '%s': %v", src, err)
+		}
+		return WriteSubtitleFile(dst, transcript.Format, Linear(transcript, scale, offset))
+	}
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("error creating output file: %v", err)
+	}
+	writer := bufio.NewWriter(out)
+	if err := rewrite(writer); err != nil {
+		out.Close()
+		return fmt.Errorf("error retiming subtitle file '%s': %v", src, err)
+	}
+	if err := writer.Flush(); err != nil {
+		out.Close()
+		return fmt.Errorf("error writing output file: %v", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("error closing output file: %v", err)
+	}
+	return nil
+}
+
+// assKaraokeDuration 匹配卡拉 OK 标签中以厘秒为单位的时长
+var assKaraokeDuration = regexp.MustCompile(`(\\(?:kf|ko|k|K))(\d+)`)
+
+// retimeASS 逐行复制 ASS 文件，只改写 [Events] 中 Dialogue/Comment 的 Start 和 End。
+// 缩放时卡拉 OK 标签的时长同比缩放，其他标签中的相对时间保持不变
+func retimeASS(r io.Reader, w io.Writer, transform func(time.Duration) time.Duration, scale float64) error {
+	// 用 ReadString 而不是 Scanner 读取，保留原来的换行符
+	reader := bufio.NewReader(r)
+	section := ""
+	var eventFormat []string
+	for lineNo := 1; ; lineNo++ {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("error reading subtitle file: %v", readErr)
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]") {
+			section = strings.ToLower(trimmed)
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if section == "[events]" && ok {
+			switch key {
+			case "Format":
+				eventFormat = splitASSFormat(value)
+			case "Dialogue", "Comment":
+				if eventFormat == nil {
+					return fmt.Errorf("event before Format line at line %d", lineNo)
+				}
+				eol := line[len(strings.TrimRight(line, "\r\n")):]
+				event, keep, err := retimeASSEvent(key, value, eventFormat, transform, scale)
+				if err != nil {
+					return fmt.Errorf("line %d: %v", lineNo, err)
+				}
+				line = ""
+				if keep {
+					line = event + eol
+				}
+			}
+		}
+		if _, err := io.WriteString(w, line); err != nil {
+			return err
+		}
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// retimeASSEvent 改写一条事件的起止时间，其余字段原样保留；事件被移出时间轴时返回 false
+func retimeASSEvent(key, value string, format []string, transform func(time.Duration) time.Duration, scale float64) (string, bool, error) {
+	values := strings.SplitN(value, ",", len(format))
+	index := map[string]int{}
+	for i, name := range format {
+		if i < len(values) {
+			index[name] = i
+		}
+	}
+	startIndex, ok1 := index["Start"]
+	endIndex, ok2 := index["End"]
+	if !ok1 || !ok2 {
+		return "", false, fmt.Errorf("event without Start and End fields")
+	}
+	start, err := parseASSTimestamp(values[startIndex])
+	if err != nil {
+		return "", false, err
+	}
+	end, err := parseASSTimestamp(values[endIndex])
+	if err != nil {
+		return "", false, err
+	}
+	start, end, keep := retimeSpan(start, end, transform)
+	if !keep {
+		return "", false, nil
+	}
+	values[startIndex] = formatASSTimestamp(start)
+	values[endIndex] = formatASSTimestamp(end)
+
+	if textIndex, ok := index["Text"]; ok && scale != 1 {
+		values[textIndex] = assKaraokeDuration.ReplaceAllStringFunc(values[textIndex], func(tag string) string {
+			m := assKaraokeDuration.FindStringSubmatch(tag)
+			cs, _ := strconv.Atoi(m[2])
+			return m[1] + strconv.Itoa(int(math.Round(float64(cs)*scale)))
+		})
+	}
+	return key + ": " + strings.TrimLeft(strings.Join(values, ","), " "), true, nil
+}
+
+// vttInlineTimestamp 匹配 WebVTT 文本中的内联时间戳，如 "<00:01.500>"
+var vttInlineTimestamp = regexp.MustCompile(`<((?:\d+:)?\d{2}:\d{2}\.\d{3})>`)
+
+// retimeVTT 按块复制 WebVTT 文件，只改写时间行和内联时间戳，设置、样式和注释块原样保留
+func retimeVTT(r io.Reader, w io.Writer, transform func(time.Duration) time.Duration) error {
+	first := true
+	return scanBlocks(r, func(block []string) error {
+		timing := -1
+		for i, line := range block {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if !first && timing >= 0 && timing <= 1 && !isVTTKeywordBlock(block[0], "NOTE") {
+			start, end, settings, err := parseVTTTiming(block[timing])
+			if err != nil {
+				return fmt.Errorf("error parsing cue timing: %v", err)
+			}
+			start, end, keep := retimeSpan(start, end, transform)
+			if !keep {
+				return nil
+			}
+			block[timing] = formatClock(start, ".") + " --> " + formatClock(end, ".")
+			if settings != "" {
+				block[timing] += " " + settings
+			}
+			for i := timing + 1; i < len(block); i++ {
+				block[i] = vttInlineTimestamp.ReplaceAllStringFunc(block[i], func(tag string) string {
+					t, err := parseVTTTimestamp(tag[1 : len(tag)-1])
+					if err != nil {
+						return tag
+					}
+					return "<" + formatClock(transform(t), ".") + ">"
+				})
+			}
+		}
+		separator := "\n"
+		if first {
+			separator = ""
+		}
+		first = false
+		_, err := io.WriteString(w, separator+strings.Join(block, "\n")+"\n")
+		return err
+	})
+}
@@ -0,0 +1,111 @@
+package subtitles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func retimeSample() *Transcript {
+	return &Transcript{Cues: []Cue{
+		{Index: 1, Start: 500 * time.Millisecond, End: 2 * time.Second, Text: "一"},
+		{Index: 2, Start: 60 * time.Second, End: 62 * time.Second, Text: "二"},
+		{Index: 3, Text: "没有时间", Flags: FlagUntimed},
+	}}
+}
+
+// TestRetime tests shift, frame rate scaling and two-point resync.
+func TestRetime(t *testing.T) {
+	shifted := Shift(retimeSample(), -time.Second)
+	if len(shifted.Cues) != 3 || shifted.Cues[0].Start != 0 || shifted.Cues[0].End != time.Second {
+		t.Errorf("shift: %+v", shifted.Cues)
+	}
+	if dropped := Shift(retimeSample(), -5*time.Second); dropped.Cues[0].Text != "二" {
+		t.Errorf("shift: cues ending before zero should be dropped: %+v", dropped.Cues)
+	}
+
+	scaled, err := ScaleFrameRate(retimeSample(), 25, 23.976)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := scaled.Cues[1].Start, 62563*time.Millisecond; got != want {
+		t.Errorf("fps: start = %v, want %v", got, want)
+	}
+
+	// 字幕在 1 秒处慢了 1 秒，在 61 秒处慢了 4 秒
+	resynced, err := Resync(retimeSample(), Anchor{From: time.Second, To: 2 * time.Second}, Anchor{From: 61 * time.Second, To: 65 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resynced.Cues[1].Start, 63950*time.Millisecond; got != want {
+		t.Errorf("resync: start = %v, want %v", got, want)
+	}
+	if resynced.Cues[2].Start != 0 || !resynced.Cues[2].Has(FlagUntimed) {
+		t.Errorf("resync: untimed cue changed: %+v", resynced.Cues[2])
+	}
+
+	if _, err := Resync(retimeSample(), Anchor{From: time.Second}, Anchor{From: time.Second, To: time.Minute}); err == nil {
+		t.Error("expected error for identical anchors")
+	}
+}
+
+// TestRetimeFileASS tests that retiming an ASS file keeps its header, PlayRes, override tags and drawings.
+func TestRetimeFileASS(t *testing.T) {
+	source, err := os.ReadFile("testdata/retime.ass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := filepath.Join(t.TempDir(), "retimed.ass")
+	if err := RetimeFile("testdata/retime.ass", dst, 1, 1500*time.Millisecond, ParseOptions{}); err != nil {
+		t.Fatalf("RetimeFile returned error: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.NewReplacer(
+		"0:00:01.00,0:00:03.50", "0:00:02.50,0:00:05.00",
+		"0:00:04.00,0:00:06.00", "0:00:05.50,0:00:07.50",
+		"0:00:05.00,0:00:06.00", "0:00:06.50,0:00:07.50",
+		"0:00:07.00,0:00:08.00", "0:00:08.50,0:00:09.50",
+	).Replace(string(source))
+	if string(got) != want {
+		t.Errorf("retimed file:\n%s\nwant:\n%s", got, want)
+	}
+
+	// 缩放时卡拉 OK 的时长同比缩放
+	if err := RetimeFile("testdata/retime.ass", dst, 2, 0, ParseOptions{}); err != nil {
+		t.Fatalf("RetimeFile returned error: %v", err)
+	}
+	got, err = os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(got), "Dialogue: 1,0:00:08.00,0:00:12.00,OP,,0,0,0,,{\\k40}星{\\k60}之{\\kf100}歌\n") {
+		t.Errorf("karaoke line not scaled:\n%s", got)
+	}
+}
+
+// TestRetimeFileVTT tests that retiming a WebVTT file keeps settings, tags and notes and moves inline timestamps.
+func TestRetimeFileVTT(t *testing.T) {
+	dst := filepath.Join(t.TempDir(), "retimed.vtt")
+	if err := RetimeFile("testdata/sample.vtt", dst, 1, -time.Second, ParseOptions{}); err != nil {
+		t.Fatalf("RetimeFile returned error: %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"WEBVTT - 采访片段\nKind: captions\n",
+		"NOTE\n这段注释不应出现在字幕中\n",
+		"intro\n00:00:00.000 --> 00:00:02.200 align:start position:10%\n<v 主持人>欢迎来到 <b>本期</b> 节目</v>\n",
+		"<00:00:05.000>卡拉<00:00:06.000>OK &amp; 字幕 &lt;测试&gt;",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("retimed file does not contain %q:\n%s", want, got)
+		}
+	}
+}
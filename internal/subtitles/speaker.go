@@ -0,0 +1,88 @@
+package subtitles
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// minSpeakerCues 是文本开头的 "姓名：" 被当作说话人所需的最少条目数，
+// 只出现一次的标签更可能是 "注意：" 之类的正文
+const minSpeakerCues = 2
+
+// maxSpeakerNameLength 是不含空格的说话人标签的最大字数；"我觉得最重要的一点是：" 之类的长标签是正文
+const maxSpeakerNameLength = 8
+
+// maxSpeakerNameWords 是含空格的说话人标签（"John Smith"）的最大词数
+const maxSpeakerNameWords = 3
+
+// speakerNameEndings 是不会出现在姓名或称呼末尾的字，以它们结尾的标签是 "比如说：" 之类的正文
+const speakerNameEndings = "是说讲道的了吗呢吧啊呀就有在要"
+
+// ExtractSpeakers 把文本以 "姓名：" 或 "- 姓名：" 开头的条目的标签移到 Speaker 字段，
+// 返回副本。标签必须像姓名（见 isSpeakerName）且至少出现 minSpeakerCues 次。
+// 已有说话人的条目（WebVTT 的 <v>、ASS 的 Name 字段等）不做处理
+func ExtractSpeakers(transcript *Transcript) *Transcript {
+	counts := map[string]int{}
+	for _, cue := range transcript.Cues {
+		if cue.Speaker == "" {
+			if speaker, _ := speakerPrefix(cue.Text); isSpeakerName(speaker) {
+				counts[speaker]++
+			}
+		}
+	}
+
+	result := *transcript
+	result.Cues = make([]Cue, len(transcript.Cues))
+	for i, cue := range transcript.Cues {
+		if cue.Speaker == "" {
+			if speaker, text := speakerPrefix(cue.Text); counts[speaker] >= minSpeakerCues {
+				cue.Speaker, cue.Text = speaker, text
+			}
+		}
+		result.Cues[i] = cue
+	}
+	return &result
+}
+
+// Speakers 返回字幕中出现的说话人，顺序与首次出现的顺序一致；注释行不计入
+func (t *Transcript) Speakers() []string {
+	var speakers []string
+	seen := map[string]bool{}
+	for _, cue := range t.Cues {
+		if cue.Speaker != "" && !cue.Has(FlagComment) && !seen[cue.Speaker] {
+			seen[cue.Speaker] = true
+			speakers = append(speakers, cue.Speaker)
+		}
+	}
+	return speakers
+}
+
+// speakerPrefix 分离出文本第一行开头的说话人标签，没有标签时 speaker 为空
+func speakerPrefix(text string) (string, string) {
+	first, rest, multiline := strings.Cut(text, "\n")
+	speaker, first := splitSpeaker(strings.TrimLeft(first, "-–— "))
+	if speaker == "" || first == "" {
+		return "", text
+	}
+	if multiline {
+		first += "\n" + rest
+	}
+	return strings.TrimSpace(speaker), first
+}
+
+// isSpeakerName 判断标签是否像姓名或称呼：不含空格时不超过 maxSpeakerNameLength 个字，
+// 含空格时不超过 maxSpeakerNameWords 个词，且不以 speakerNameEndings 中的字结尾
+func isSpeakerName(label string) bool {
+	if label == "" {
+		return false
+	}
+	if words := strings.Fields(label); len(words) > 1 {
+		if len(words) > maxSpeakerNameWords {
+			return false
+		}
+	} else if utf8.RuneCountInString(label) > maxSpeakerNameLength {
+		return false
+	}
+	last, _ := utf8.DecodeLastRuneInString(label)
+	return !strings.ContainsRune(speakerNameEndings, last)
+}
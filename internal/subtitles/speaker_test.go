@@ -0,0 +1,68 @@
+package subtitles
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExtractSpeakers tests that recurring name prefixes become speakers and one-off labels stay text.
+func TestExtractSpeakers(t *testing.T) {
+	transcript := &Transcript{Cues: []Cue{
+		{Index: 1, Text: "主持人：欢迎收听"},
+		{Index: 2, Text: "- 嘉宾：谢谢邀请"},
+		{Index: 3, Text: "注意：以下内容纯属虚构"},
+		{Index: 4, Text: "主持人：我们开始吧\n第一个问题"},
+		{Index: 5, Text: "嘉宾: 好的"},
+		{Index: 6, Speaker: "旁白", Text: "主持人：不会被拆开"},
+	}}
+
+	result := ExtractSpeakers(transcript)
+	var got [][2]string
+	for _, cue := range result.Cues {
+		got = append(got, [2]string{cue.Speaker, cue.Text})
+	}
+	want := [][2]string{
+		{"主持人", "欢迎收听"},
+		{"嘉宾", "谢谢邀请"},
+		{"", "注意：以下内容纯属虚构"},
+		{"主持人", "我们开始吧\n第一个问题"},
+		{"嘉宾", "好的"},
+		{"旁白", "主持人：不会被拆开"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+	if transcript.Cues[0].Speaker != "" {
+		t.Error("ExtractSpeakers modified its input")
+	}
+
+	if speakers, want := result.Speakers(), []string{"主持人", "嘉宾", "旁白"}; !reflect.DeepEqual(speakers, want) {
+		t.Errorf("Speakers() = %q, want %q", speakers, want)
+	}
+	wantText := "主持人：欢迎收听, \n嘉宾：谢谢邀请, 注意：以下内容纯属虚构, \n主持人：我们开始吧, 第一个问题, \n嘉宾：好的, \n旁白：主持人：不会被拆开, "
+	if text := result.Text(); text != wantText {
+		t.Errorf("Text() = %q, want %q", text, wantText)
+	}
+}
+
+// TestExtractSpeakersIgnoresProse tests that recurring sentence openings ending in a colon are not taken for speakers.
+func TestExtractSpeakersIgnoresProse(t *testing.T) {
+	transcript := &Transcript{Cues: []Cue{
+		{Index: 1, Text: "我觉得最重要的一点是：坚持"},
+		{Index: 2, Text: "比如说：每天早起"},
+		{Index: 3, Text: "我觉得最重要的一点是：耐心"},
+		{Index: 4, Text: "比如说：按时吃饭"},
+		{Index: 5, Text: "John Smith: hello"},
+		{Index: 6, Text: "John Smith: goodbye"},
+	}}
+
+	result := ExtractSpeakers(transcript)
+	for i, cue := range result.Cues[:4] {
+		if cue.Speaker != "" || cue.Text != transcript.Cues[i].Text {
+			t.Errorf("cue %d: got speaker %q, text %q", cue.Index, cue.Speaker, cue.Text)
+		}
+	}
+	if speakers, want := result.Speakers(), []string{"John Smith"}; !reflect.DeepEqual(speakers, want) {
+		t.Errorf("Speakers() = %q, want %q", speakers, want)
+	}
+}
@@ -0,0 +1,298 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SRTSubtitleParser 实现了 SubtitleParser 接口，专门处理 SRT 或 TXT 格式。
+// 解析器是一个容错的状态机：序号缺失或错乱、缺少空行、时间码不规范等问题
+// 不会中断解析，而是记录到 Transcript.Warnings 中
+type SRTSubtitleParser struct{}
+
+// SRTSubtitleWriter 实现了 SubtitleWriter 接口，输出 SRT 格式
+type SRTSubtitleWriter struct{}
+
+type srtState int
+
+const (
+	srtExpectIndex  srtState = iota // 等待序号或时间行
+	srtExpectTiming                 // 已读到序号，等待时间行
+	srtText                         // 读取字幕文本
+)
+
+var (
+	srtTimingLine      = regexp.MustCompile(`^([^\s\->]+)\s*(?:-{1,3}>|—>|→)\s*(\S+)`)
+	srtTimestamp       = regexp.MustCompile(`^(?:(\d+):)?(\d{1,2}):(\d{1,2})(?:[,.:](\d+))?$`)
+	srtStrictTimestamp = regexp.MustCompile(`^\d{2}:\d{2}:\d{2},\d{3}$`)
+)
+
+// srtParser 保存一次解析的状态
+type srtParser struct {
+	meta *Transcript
+	emit CueFunc
+
+	state       srtState
+	current     *Cue // 正在读取的条目，遇到下一个条目或文件结束时才输出
+	currentLine int
+	pendingNo   int    // 已读到但还没有时间行的序号
+	heldDigits  string // 文本中的纯数字行，要看下一行才能判断是文本还是序号
+	heldLine    int
+	lastIndex   int
+	count       int
+
+	warnedFormat bool
+}
+
+// Parse 解析 SRT/TXT 格式字幕文件
+func (p *SRTSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	// 使用 bufio.Scanner 逐行读取文件内容
+	scanner := newLineScanner(r)
+	meta.Format = FormatSRT
+	parser := &srtParser{meta: meta, emit: emit}
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := parser.line(lineNo, strings.TrimSpace(scanner.Text())); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading subtitle file: %v", err)
+	}
+
+	return parser.finish(lineNo)
+}
+
+// Write 将字幕写出为 SRT 格式；序号重新从 1 开始编号，注释行不会写出
+func (w *SRTSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	writer := bufio.NewWriter(out)
+	index := 0
+	for _, cue := range transcript.Cues {
+		if cue.Has(FlagComment) {
+			continue
+		}
+		index++
+		if index > 1 {
+			writer.WriteString("\n")
+		}
+		fmt.Fprintf(writer, "%d\n%s --> %s\n%s\n", index, formatClock(cue.Start, ","), formatClock(cue.End, ","), cue.Text)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing SRT: %v", err)
+	}
+	return nil
+}
+
+// line 处理一行内容
+func (p *srtParser) line(lineNo int, line string) error {
+	// 上一行是文本中的纯数字，这一行决定它的含义
+	if p.heldDigits != "" {
+		held, heldLine := p.heldDigits, p.heldLine
+		p.heldDigits = ""
+		if isSRTTiming(line) {
+			p.warn(heldLine, "missing blank line before cue %s", held)
+			n, _ := strconv.Atoi(held)
+			return p.startCue(lineNo, line, n)
+		}
+		p.appendText(held)
+	}
+
+	switch p.state {
+	case srtText:
+		switch {
+		case line == "":
+			p.state = srtExpectIndex
+		case isSRTTiming(line):
+			p.warn(lineNo, "missing blank line and cue number before timecode")
+			return p.startCue(lineNo, line, 0)
+		case allDigits(line):
+			p.heldDigits, p.heldLine = line, lineNo
+		default:
+			p.appendText(line)
+		}
+
+	case srtExpectIndex:
+		switch {
+		case line == "":
+		case allDigits(line):
+			p.pendingNo, _ = strconv.Atoi(line)
+			p.state = srtExpectTiming
+		case isSRTTiming(line):
+			p.warn(lineNo, "missing cue number before timecode")
+			return p.startCue(lineNo, line, 0)
+		case p.current != nil && !p.current.Has(FlagUntimed):
+			// 空行之后的文本仍属于上一条字幕
+			p.warn(lineNo, "blank line inside cue %d", p.current.Index)
+			p.appendText(line)
+			p.state = srtText
+		default:
+			// 没有时间戳的纯文本行（TXT），每行作为一条
+			if err := p.flush(); err != nil {
+				return err
+			}
+			p.count++
+			return p.emit(Cue{Index: p.count, Text: line, Flags: FlagUntimed})
+		}
+
+	case srtExpectTiming:
+		switch {
+		case isSRTTiming(line):
+			return p.startCue(lineNo, line, p.pendingNo)
+		case line == "":
+			p.warn(lineNo, "cue %d has no timecode or text", p.pendingNo)
+			p.state = srtExpectIndex
+		default:
+			p.warn(lineNo, "missing timecode after cue number %d", p.pendingNo)
+			if err := p.flush(); err != nil {
+				return err
+			}
+			p.current = &Cue{Index: p.nextIndex(lineNo, p.pendingNo), Flags: FlagUntimed}
+			p.currentLine = lineNo
+			p.appendText(line)
+			p.state = srtText
+		}
+	}
+	return nil
+}
+
+// startCue 输出上一条字幕并根据时间行开始新的一条；index 为 0 表示序号缺失
+func (p *srtParser) startCue(lineNo int, line string, index int) error {
+	if err := p.flush(); err != nil {
+		return err
+	}
+
+	cue := &Cue{Index: p.nextIndex(lineNo, index)}
+	start, end, err := p.parseTiming(lineNo, line)
+	if err != nil {
+		p.warn(lineNo, "%v", err)
+		cue.Flags |= FlagUntimed
+	} else {
+		cue.Start, cue.End = start, end
+		if end < start {
+			p.warn(lineNo, "cue %d ends before it starts", cue.Index)
+		}
+	}
+
+	p.current, p.currentLine = cue, lineNo
+	p.state = srtText
+	return nil
+}
+
+// nextIndex 返回新条目的序号，并检查序号是否连续
+func (p *srtParser) nextIndex(lineNo, index int) int {
+	switch {
+	case index == 0:
+		index = p.lastIndex + 1
+	case p.lastIndex != 0 && index != p.lastIndex+1:
+		p.warn(lineNo, "cue number %d out of sequence (expected %d)", index, p.lastIndex+1)
+	}
+	p.lastIndex = index
+	return index
+}
+
+// appendText 向当前条目追加一行文本
+func (p *srtParser) appendText(line string) {
+	if p.current.Text != "" {
+		p.current.Text += "\n"
+	}
+	p.current.Text += line
+}
+
+// flush 输出当前条目
+func (p *srtParser) flush() error {
+	cue := p.current
+	p.current = nil
+	if cue == nil {
+		return nil
+	}
+	if cue.Text == "" {
+		p.warn(p.currentLine, "cue %d has no text", cue.Index)
+		return nil
+	}
+	p.count++
+	return p.emit(*cue)
+}
+
+// finish 在文件结束时输出剩余内容
+func (p *srtParser) finish(lastLine int) error {
+	if p.heldDigits != "" {
+		p.appendText(p.heldDigits)
+		p.heldDigits = ""
+	}
+	if p.state == srtExpectTiming {
+		p.warn(lastLine, "cue %d at end of file has no timecode", p.pendingNo)
+	}
+	return p.flush()
+}
+
+// warn 记录一条警告
+func (p *srtParser) warn(lineNo int, format string, args ...interface{}) {
+	p.meta.Warnings = append(p.meta.Warnings, Warning{Line: lineNo, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseTiming 解析时间行，遇到不规范但可以理解的写法时只记录一次警告
+func (p *srtParser) parseTiming(lineNo int, line string) (time.Duration, time.Duration, error) {
+	start, end, strict, err := parseSRTTimecode(line)
+	if err == nil && !strict && !p.warnedFormat {
+		p.warnedFormat = true
+		p.warn(lineNo, "non-standard timecode %q (expected hh:mm:ss,mmm; further occurrences not reported)", line)
+	}
+	return start, end, err
+}
+
+// isSRTTiming 判断一行是否为时间行：箭头前必须是时间，"A --> B" 之类含箭头的对白仍是文本
+func isSRTTiming(line string) bool {
+	m := srtTimingLine.FindStringSubmatch(line)
+	return m != nil && srtTimestamp.MatchString(m[1])
+}
+
+// parseSRTTimecode 解析 "00:00:01,000 --> 00:00:02,500" 形式的时间行，
+// 结束时间之后的位置信息会被忽略；strict 表示时间码完全符合 SRT 规范
+func parseSRTTimecode(line string) (time.Duration, time.Duration, bool, error) {
+	m := srtTimingLine.FindStringSubmatch(line)
+	if m == nil {
+		return 0, 0, false, fmt.Errorf("invalid timecode %q", line)
+	}
+	start, err := parseSRTTimestamp(m[1])
+	if err != nil {
+		return 0, 0, false, err
+	}
+	end, err := parseSRTTimestamp(m[2])
+	if err != nil {
+		return 0, 0, false, err
+	}
+	strict := srtStrictTimestamp.MatchString(m[1]) && srtStrictTimestamp.MatchString(m[2]) && strings.Contains(line, " --> ")
+	return start, end, strict, nil
+}
+
+// parseSRTTimestamp 解析 "hh:mm:ss,mmm" 形式的时间，同时容忍常见的不规范写法：
+// 省略前导零（"0:0:2,14" 表示 2.14 秒）、省略小时、用 "." 或 ":" 代替 ","。
+// 小数部分按十进制小数处理，不足三位时补零，超过三位时截断到毫秒
+func parseSRTTimestamp(s string) (time.Duration, error) {
+	m := srtTimestamp.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	if minutes > 59 || seconds > 59 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+
+	if frac := m[4]; frac != "" {
+		ms, _ := strconv.Atoi((frac + "00")[:3])
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, nil
+}
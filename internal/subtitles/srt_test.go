@@ -0,0 +1,131 @@
+package subtitles
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSRTParserNumericText tests that a subtitle line made only of digits is kept as text.
+func TestSRTParserNumericText(t *testing.T) {
+	data := "1\n00:00:01,000 --> 00:00:02,000\n那一年是\n2024\n\n2\n00:00:02,000 --> 00:00:03,000\n2024\n"
+
+	transcript, err := ParseAll(&SRTSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	var texts []string
+	for _, cue := range transcript.Cues {
+		texts = append(texts, cue.Text)
+	}
+	if want := []string{"那一年是\n2024", "2024"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+	if len(transcript.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", transcript.Warnings)
+	}
+}
+
+// TestSRTParserArrowText tests that an arrow inside the subtitle text does not start a new cue.
+func TestSRTParserArrowText(t *testing.T) {
+	data := "1\n00:00:01,000 --> 00:00:02,000\n步骤\nA --> B\n\n2\n00:00:02,000 --> 00:00:03,000\n完成\n"
+
+	transcript, err := ParseAll(&SRTSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	var texts []string
+	for _, cue := range transcript.Cues {
+		texts = append(texts, cue.Text)
+		if cue.Has(FlagUntimed) {
+			t.Errorf("cue %d is untimed", cue.Index)
+		}
+	}
+	if want := []string{"步骤\nA --> B", "完成"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("texts = %q, want %q", texts, want)
+	}
+	if len(transcript.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", transcript.Warnings)
+	}
+}
+
+// TestSRTParserWarnings tests that malformed input is recovered from and reported with line numbers.
+func TestSRTParserWarnings(t *testing.T) {
+	data := strings.Join([]string{
+		"1",
+		"0:0:0,28 --> 0:0:2,14", // 2: non-standard timecode
+		"第一句",
+		"3",                             // 4: missing blank line before cue 3
+		"00:00:03,000 --> 00:00:04,000", // 5: out of sequence
+		"第三句",
+		"",
+		"仍然是第三句", // 8: blank line inside cue
+		"",
+		"00:00:05,000 --> 00:00:04,000", // 10: missing number, ends before it starts
+		"第四句",
+		"",
+		"5",
+		"没有时间码", // 14: missing timecode
+		"",
+		"6",
+		"00:00:07,000 --> 00:00:08,000", // 17: no text
+		"",
+	}, "\n")
+
+	transcript, err := ParseAll(&SRTSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+
+	type summary struct {
+		Index int
+		Start time.Duration
+		Text  string
+		Flags CueFlag
+	}
+	var got []summary
+	for _, cue := range transcript.Cues {
+		got = append(got, summary{cue.Index, cue.Start, cue.Text, cue.Flags})
+	}
+	want := []summary{
+		{1, 280 * time.Millisecond, "第一句", 0},
+		{3, 3 * time.Second, "第三句\n仍然是第三句", 0},
+		{4, 5 * time.Second, "第四句", 0},
+		{5, 0, "没有时间码", FlagUntimed},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cues = %+v\nwant %+v", got, want)
+	}
+
+	var lines []int
+	for _, w := range transcript.Warnings {
+		lines = append(lines, w.Line)
+	}
+	if wantLines := []int{2, 4, 5, 8, 10, 10, 14, 17}; !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("warning lines = %v, want %v\nwarnings: %v", lines, wantLines, transcript.Warnings)
+	}
+}
+
+// TestParseSRTTimestamp tests the lenient timestamp forms.
+func TestParseSRTTimestamp(t *testing.T) {
+	tests := map[string]time.Duration{
+		"00:00:01,000":  time.Second,
+		"0:0:2,14":      2*time.Second + 140*time.Millisecond,
+		"0:0:2,1400":    2*time.Second + 140*time.Millisecond,
+		"00:00:02.5":    2*time.Second + 500*time.Millisecond,
+		"01:02:03.456":  time.Hour + 2*time.Minute + 3*time.Second + 456*time.Millisecond,
+		"00:01,500":     time.Second + 500*time.Millisecond,
+		"00:00:01:250":  time.Second + 250*time.Millisecond,
+		"00:00:01,2345": time.Second + 234*time.Millisecond,
+	}
+	for input, want := range tests {
+		got, err := parseSRTTimestamp(input)
+		if err != nil || got != want {
+			t.Errorf("parseSRTTimestamp(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := parseSRTTimestamp("00:61:00,000"); err == nil {
+		t.Errorf("expected error for minutes out of range")
+	}
+}
@@ -2,20 +2,54 @@ package subtitles
 
 import (
 	"bufio"
-	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"os"
-	"strings"
+	"time"
 )
 
-// SubtitleParser 是一个通用的字幕解析器接口
+// CueFunc 接收解析器输出的每一条字幕，返回错误时解析立即停止
+type CueFunc func(cue Cue) error
+
+// SubtitleParser 是一个通用的字幕解析器接口。
+// Parse 从 r 中增量读取字幕，每解析出一条就交给 emit，
+// 并把格式、语言等文件级信息写入 meta（不会修改 meta.Cues）
 type SubtitleParser interface {
-	Parse(filePath string, fileData []byte) (string, error)
+	Parse(r io.Reader, meta *Transcript, emit CueFunc) error
 }
 
-// SRTSubtitleParser 实现了 SubtitleParser 接口，专门处理 SRT 或 TXT 格式
-type SRTSubtitleParser struct{}
+// maxLineSize 是按行解析时允许的最长一行
+const maxLineSize = 4 << 20
+
+// newLineScanner 创建一个缓冲区足够大的逐行扫描器
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), maxLineSize)
+	return scanner
+}
+
+// ParseAll 使用 parser 解析 r 中的全部字幕并收集为 Transcript
+func ParseAll(parser SubtitleParser, r io.Reader) (*Transcript, error) {
+	transcript := &Transcript{}
+	err := parser.Parse(r, transcript, func(cue Cue) error {
+		transcript.Cues = append(transcript.Cues, cue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return transcript, nil
+}
+
+// utf8BOM 是部分编辑器在 UTF-8 文件开头写入的字节序标记
+var utf8BOM = []byte("\uFEFF")
+
+// SubtitleWriter 将 Transcript 写出为某种字幕格式
+type SubtitleWriter interface {
+	Write(w io.Writer, transcript *Transcript) error
+}
 
 type SubtitleContent struct {
 	From     float64 `json:"from"`
@@ -46,119 +80,290 @@ type NewSubtitleFormat struct {
 	Body            []SubtitleContent `json:"body"`
 }
 
-// Parse 解析 SRT/TXT 格式字幕文件
-func (p *SRTSubtitleParser) Parse(filePath string, fileData []byte) (string, error) {
-	// 使用 bufio.Scanner 逐行读取文件内容
-	scanner := bufio.NewScanner(bytes.NewReader(fileData))
-	var paragraph strings.Builder
+// Parse 解析旧 JSON 格式字幕文件，逐条解码数组元素
+func (p *OldJSONSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	meta.Format = FormatOldJSON
+	decoder := json.NewDecoder(r)
+
+	if _, err := enterJSONArray(decoder); err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+	if err := decodeContents(decoder, emit); err != nil {
+		return err
+	}
+	return nil
+}
 
-	// 逐行处理文件
-	for scanner.Scan() {
-		line := scanner.Text()
+// Parse 解析新 JSON 格式字幕文件，body 中的条目逐条解码，其余字段作为文件级信息
+func (p *NewJSONSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	meta.Format = FormatBCC
+	decoder := json.NewDecoder(r)
 
-		// 跳过时间戳行、序号行和空行
-		if line == "" || strings.Contains(line, "-->") || allDigits(line) {
+	if err := expectJSONDelim(decoder, '{'); err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+	header := map[string]json.RawMessage{}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+		if key == "body" {
+			isArray, err := enterJSONArray(decoder)
+			if err != nil {
+				return fmt.Errorf("error decoding JSON body: %v", err)
+			}
+			if isArray {
+				if err := decodeContents(decoder, emit); err != nil {
+					return err
+				}
+			}
 			continue
 		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return fmt.Errorf("error decoding JSON: %v", err)
+		}
+		header[key.(string)] = value
+	}
+
+	// 文件级字段数量很少，统一交给 NewSubtitleFormat 解码
+	var format NewSubtitleFormat
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return fmt.Errorf("error decoding JSON: %v", err)
+	}
+	if err := json.Unmarshal(encoded, &format); err != nil {
+		return fmt.Errorf("error decoding JSON header: %v", err)
+	}
+	meta.Lang = format.Lang
+	meta.BCC = &BCCMeta{
+		FontSize:        format.FontSize,
+		FontColor:       format.FontColor,
+		BackgroundAlpha: format.BackgroundAlpha,
+		BackgroundColor: format.BackgroundColor,
+		Stroke:          format.Stroke,
+		Type:            format.Type,
+		Version:         format.Version,
+	}
+	return nil
+}
+
+// OldJSONSubtitleWriter 实现了 SubtitleWriter 接口，输出旧 JSON 格式
+type OldJSONSubtitleWriter struct{}
 
-		paragraph.WriteString(line + ", ")
+// NewJSONSubtitleWriter 实现了 SubtitleWriter 接口，输出 B 站 BCC JSON 格式
+type NewJSONSubtitleWriter struct{}
+
+// Write 将字幕写出为旧 JSON 格式
+func (w *OldJSONSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	if err := json.NewEncoder(out).Encode(OldSubtitleFormat(cuesToContents(transcript.Cues))); err != nil {
+		return fmt.Errorf("error writing JSON: %v", err)
 	}
+	return nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("error reading subtitle file: %v", err)
+// Write 将字幕写出为 BCC JSON 格式，保留来源中的显示设置（来源为 ASS 时从样式推断），缺省时使用 B 站默认值
+func (w *NewJSONSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	meta := defaultBCCMeta
+	if transcript.BCC != nil {
+		meta = *transcript.BCC
+	} else if derived := assToBCCMeta(transcript.Styles); derived != nil {
+		meta = *derived
+	}
+	format := NewSubtitleFormat{
+		FontSize:        meta.FontSize,
+		FontColor:       meta.FontColor,
+		BackgroundAlpha: meta.BackgroundAlpha,
+		BackgroundColor: meta.BackgroundColor,
+		Stroke:          meta.Stroke,
+		Type:            meta.Type,
+		Lang:            transcript.Lang,
+		Version:         meta.Version,
+		Body:            cuesToContents(transcript.Cues),
 	}
+	if err := json.NewEncoder(out).Encode(format); err != nil {
+		return fmt.Errorf("error writing JSON: %v", err)
+	}
+	return nil
+}
 
-	return paragraph.String(), nil
+// defaultBCCMeta 是 B 站网页端上传字幕时的默认显示设置
+var defaultBCCMeta = BCCMeta{
+	FontSize:        0.4,
+	FontColor:       "#FFFFFF",
+	BackgroundAlpha: 0.5,
+	BackgroundColor: "#9C27B0",
+	Stroke:          "none",
 }
 
-// Parse 解析旧 JSON 格式字幕文件
-func (p *OldJSONSubtitleParser) Parse(filePath string, fileData []byte) (string, error) {
-	// 尝试解析为旧格式的 JSON
-	var subtitles []SubtitleContent
-	if err := json.Unmarshal(fileData, &subtitles); err != nil {
-		return "", fmt.Errorf("error decoding JSON in file '%s': %v", filePath, err)
+// cuesToContents 将 Cue 转换为 JSON 字幕条目；注释行不会写出
+func cuesToContents(cues []Cue) []SubtitleContent {
+	contents := make([]SubtitleContent, 0, len(cues))
+	for _, cue := range cues {
+		if cue.Has(FlagComment) {
+			continue
+		}
+		location := cue.Location
+		if location == 0 {
+			location = LocationBottom
+		}
+		contents = append(contents, SubtitleContent{
+			From:     durationToSeconds(cue.Start),
+			To:       durationToSeconds(cue.End),
+			Sid:      len(contents) + 1,
+			Location: location,
+			Content:  cue.Text,
+			Music:    cue.Music,
+		})
 	}
+	return contents
+}
 
-	// 使用 strings.Builder 高效拼接字幕内容
-	var paragraph strings.Builder
-	for _, subtitle := range subtitles {
-		paragraph.WriteString(subtitle.Content + ", ")
+// decodeContents 逐条解码 JSON 数组中的字幕条目，直到数组结束
+func decodeContents(decoder *json.Decoder, emit CueFunc) error {
+	for i := 0; decoder.More(); i++ {
+		var content SubtitleContent
+		if err := decoder.Decode(&content); err != nil {
+			return fmt.Errorf("error decoding JSON subtitle entry %d: %v", i+1, err)
+		}
+		if err := emit(contentToCue(content, i)); err != nil {
+			return err
+		}
 	}
+	return expectJSONDelim(decoder, ']')
+}
 
-	// 返回拼接后的字幕文本
-	return paragraph.String(), nil
+// expectJSONDelim 读取下一个 token 并确认是指定的分隔符
+func expectJSONDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := token.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q, got %v", delim, token)
+	}
+	return nil
 }
 
-// Parse 解析新 JSON 格式字幕文件
-func (p *NewJSONSubtitleParser) Parse(filePath string, fileData []byte) (string, error) {
-	// 尝试解析为新的 JSON 格式
-	var format NewSubtitleFormat
-	if err := json.Unmarshal(fileData, &format); err != nil {
-		return "", fmt.Errorf("error decoding JSON in file '%s': %v", filePath, err)
+// enterJSONArray 读取数组的开头；值为 null 时返回 false
+func enterJSONArray(decoder *json.Decoder) (bool, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return false, err
+	}
+	if token == nil {
+		return false, nil
 	}
+	if d, ok := token.(json.Delim); !ok || d != '[' {
+		return false, fmt.Errorf("expected array, got %v", token)
+	}
+	return true, nil
+}
 
-	// 使用 strings.Builder 高效拼接字幕内容
-	var paragraph strings.Builder
-	for _, subtitle := range format.Body {
-		paragraph.WriteString(subtitle.Content + ",")
+// contentToCue 将第 i 条 JSON 字幕条目转换为 Cue
+func contentToCue(content SubtitleContent, i int) Cue {
+	index := content.Sid
+	if index == 0 {
+		index = i + 1
+	}
+	return Cue{
+		Index:    index,
+		Start:    secondsToDuration(content.From),
+		End:      secondsToDuration(content.To),
+		Text:     content.Content,
+		Music:    content.Music,
+		Location: content.Location,
 	}
+}
 
-	// 返回拼接后的字幕文本
-	return paragraph.String(), nil
+// secondsToDuration 将以秒为单位的浮点数转换为 time.Duration，精确到毫秒
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds*1000+0.5) * time.Millisecond
 }
 
-// NewSubtitleParser 创建一个合适的字幕解析器
-func NewSubtitleParser(filePath string) (SubtitleParser, []byte, error) {
-	// 根据文件后缀名来判断选择哪个解析器
-	if strings.HasSuffix(filePath, ".srt") || strings.HasSuffix(filePath, ".txt") {
-		// 读取文件数据
-		fileData, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, nil, fmt.Errorf("error opening subtitle file: %v", err)
-		}
-		// 返回 SRT 解析器并附带文件内容
-		return &SRTSubtitleParser{}, fileData, nil
+// durationToSeconds 将 time.Duration 转换为以秒为单位的浮点数，精确到毫秒
+func durationToSeconds(d time.Duration) float64 {
+	return float64(d.Milliseconds()) / 1000
+}
 
-	} else if strings.HasSuffix(filePath, ".json") {
-		// 尝试一次性读取整个文件
-		fileData, err := os.ReadFile(filePath)
-		if err != nil {
-			return nil, nil, fmt.Errorf("error opening JSON subtitle file: %v", err)
-		}
+// ParseOptions 控制字幕文件的读取方式
+type ParseOptions struct {
+	Encoding string // 强制使用的字符编码，为空时自动检测
+}
 
-		// 尝试解码为旧的 JSON 格式
-		var oldSubtitles []SubtitleContent
-		if err := json.Unmarshal(fileData, &oldSubtitles); err == nil {
-			// 如果解码成功为旧格式，则返回旧格式解析器
-			return &OldJSONSubtitleParser{}, fileData, nil
-		}
+// NewSubtitleParser 根据内容为 r 选择合适的字幕解析器。
+// 返回的 Reader 已转换为 UTF-8 并从头开始，name 仅用于按扩展名辅助探测
+func NewSubtitleParser(name string, r io.Reader, opts ParseOptions) (SubtitleParser, io.Reader, error) {
+	// 转换为 UTF-8，避免把乱码发送给模型
+	decoded, _, err := NewDecodingReader(r, opts.Encoding)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error decoding subtitle file '%s': %v", name, err)
+	}
 
-		// 尝试解码为新的 JSON 格式
-		var newSubtitles NewSubtitleFormat
-		if err := json.Unmarshal(fileData, &newSubtitles); err != nil {
-			return nil, nil, fmt.Errorf("error decoding JSON: %v", err)
-		}
+	// 只用开头的内容探测格式
+	buffered := bufio.NewReaderSize(decoded, sniffSize)
+	head, err := buffered.Peek(sniffSize)
+	if err != nil && err != io.EOF {
+		return nil, nil, fmt.Errorf("error reading subtitle file '%s': %v", name, err)
+	}
+	parser, _, err := DetectFormat(name, head)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parser, buffered, nil
+}
 
-		// 如果解码成功，说明是新的 JSON 格式
-		return &NewJSONSubtitleParser{}, fileData, nil
+// StreamSubtitles 增量解析 r 中的字幕，每条字幕解析完成后立即交给 emit，
+// 内存占用与输入大小无关。返回的 Transcript 只包含文件级信息，不包含 Cues
+func StreamSubtitles(name string, r io.Reader, opts ParseOptions, emit CueFunc) (*Transcript, error) {
+	parser, decoded, err := NewSubtitleParser(name, r, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil, nil, fmt.Errorf("unsupported subtitle file format")
+	meta := &Transcript{}
+	invalid := 0
+	err = parser.Parse(decoded, meta, func(cue Cue) error {
+		invalid += countInvalid([]byte(cue.Text))
+		return emit(cue)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error parsing subtitle file '%s': %v", name, err)
+	}
+	if invalid > 0 {
+		log.Printf("Warning: %d invalid characters remain in '%s' after decoding; try --encoding", invalid, name)
+	}
+	return meta, nil
 }
 
-// ParseSubtitleFile 封装了从文件路径到解析后的文本的所有操作
-func ParseSubtitleFile(filePath string) (string, error) {
-	// 使用工厂函数获取适当的字幕解析器以及文件内容
-	parser, fileData, err := NewSubtitleParser(filePath)
+// StreamSubtitleFile 打开字幕文件并增量解析，见 StreamSubtitles
+func StreamSubtitleFile(filePath string, opts ParseOptions, emit CueFunc) (*Transcript, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
-		return "", err
+		return nil, fmt.Errorf("error opening subtitle file: %v", err)
 	}
+	defer file.Close()
+
+	return StreamSubtitles(filePath, file, opts, emit)
+}
+
+// ParseSubtitleFile 封装了从文件路径到解析后的字幕的所有操作
+func ParseSubtitleFile(filePath string) (*Transcript, error) {
+	return ParseSubtitleFileWithOptions(filePath, ParseOptions{})
+}
 
-	// 使用解析器解析字幕文件内容
-	parsedText, err := parser.Parse(filePath, fileData)
+// ParseSubtitleFileWithOptions 与 ParseSubtitleFile 相同，但允许指定读取选项
+func ParseSubtitleFileWithOptions(filePath string, opts ParseOptions) (*Transcript, error) {
+	var cues []Cue
+	transcript, err := StreamSubtitleFile(filePath, opts, func(cue Cue) error {
+		cues = append(cues, cue)
+		return nil
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return parsedText, nil
+	transcript.Cues = cues
+	return transcript, nil
 }
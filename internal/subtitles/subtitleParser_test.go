@@ -0,0 +1,129 @@
+package subtitles
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestSRTParserKeepsTiming tests that SRT cues keep their timecodes and indices.
+func TestSRTParserKeepsTiming(t *testing.T) {
+	data := "1\n0:0:0,28 --> 0:0:2,14\n平常打混双的都知道\n\n2\n00:00:02,140 --> 00:00:06,780\n最怕就是女后男前\n女生被按在后场动弹不得\n"
+
+	transcript, err := ParseAll(&SRTSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(transcript.Cues) != 2 {
+		t.Fatalf("got %d cues, want 2", len(transcript.Cues))
+	}
+
+	first := transcript.Cues[0]
+	if first.Start != 280*time.Millisecond || first.End != 2*time.Second+140*time.Millisecond {
+		t.Errorf("first cue timing = %v --> %v", first.Start, first.End)
+	}
+	second := transcript.Cues[1]
+	if second.Index != 2 || second.Text != "最怕就是女后男前\n女生被按在后场动弹不得" {
+		t.Errorf("second cue = %+v", second)
+	}
+	if got, want := transcript.Text(), "平常打混双的都知道, 最怕就是女后男前, 女生被按在后场动弹不得, "; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+// TestNewJSONParserKeepsFields tests that BCC JSON cues keep timing, sid, music and location.
+func TestNewJSONParserKeepsFields(t *testing.T) {
+	data := `{"lang":"zh","body":[{"from":1.5,"to":3.25,"sid":7,"location":2,"content":"你好","music":0.8}]}`
+
+	transcript, err := ParseAll(&NewJSONSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if transcript.Lang != "zh" || len(transcript.Cues) != 1 {
+		t.Fatalf("unexpected transcript: %+v", transcript)
+	}
+
+	cue := transcript.Cues[0]
+	want := Cue{Index: 7, Start: 1500 * time.Millisecond, End: 3250 * time.Millisecond, Text: "你好", Music: 0.8, Location: 2}
+	if cue != want {
+		t.Errorf("cue = %+v, want %+v", cue, want)
+	}
+}
+
+// lazyReader generates its content on demand and records how many entries were produced.
+type lazyReader struct {
+	header, footer string
+	entry          func(i int) string
+	total          int
+	produced       int
+	done           bool
+	buf            bytes.Buffer
+}
+
+func (r *lazyReader) Read(p []byte) (int, error) {
+	if r.produced == 0 && r.buf.Len() == 0 {
+		r.buf.WriteString(r.header)
+	}
+	for r.buf.Len() < len(p) && r.produced < r.total {
+		r.produced++
+		r.buf.WriteString(r.entry(r.produced))
+	}
+	if r.produced == r.total && !r.done {
+		r.done = true
+		r.buf.WriteString(r.footer)
+	}
+	if r.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return r.buf.Read(p)
+}
+
+// TestStreamSubtitlesIsIncremental tests that cues are emitted before the whole input has been read.
+func TestStreamSubtitlesIsIncremental(t *testing.T) {
+	const total = 100000
+	inputs := map[string]*lazyReader{
+		"lecture.srt": {
+			entry: func(i int) string { return fmt.Sprintf("%d\n00:00:01,000 --> 00:00:02,000\n第%d条\n\n", i, i) },
+			total: total,
+		},
+		"lecture.json": {
+			header: `{"font_size":0.4,"lang":"zh","body":[`,
+			entry: func(i int) string {
+				sep := ","
+				if i == 1 {
+					sep = ""
+				}
+				return fmt.Sprintf(`%s{"from":%d,"to":%d,"sid":%d,"content":"第%d条"}`, sep, i, i+1, i, i)
+			},
+			footer: `],"version":"v1.6.0.4"}`,
+			total:  total,
+		},
+	}
+
+	for name, input := range inputs {
+		count := 0
+		producedAtFirstCue := 0
+		meta, err := StreamSubtitles(name, input, ParseOptions{}, func(cue Cue) error {
+			count++
+			if count == 1 {
+				producedAtFirstCue = input.produced
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s: StreamSubtitles returned error: %v", name, err)
+		}
+		if count != total {
+			t.Errorf("%s: got %d cues, want %d", name, count, total)
+		}
+		if producedAtFirstCue >= total {
+			t.Errorf("%s: first cue was only emitted after the whole input had been generated", name)
+		}
+		if meta.Cues != nil {
+			t.Errorf("%s: streamed transcript should not collect cues", name)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package subtitles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TXTSubtitleParser 实现了 SubtitleParser 接口，处理会议记录和语音识别工具导出的纯文本转写稿。
+// 支持的布局：
+//
+//	[00:01:23] 主持人：文本
+//	00:01 Speaker 1 文本
+//	[00:01:23 - 00:01:30] 文本
+//	说话人 1 00:01:23      （时间和说话人单独一行，文本在后面几行）
+//
+// 每条的结束时间取下一条的开始时间。第一个时间戳之前的内容以及完全没有时间戳的文件
+// 按空行分段，每段作为一条没有时间信息的条目
+type TXTSubtitleParser struct{}
+
+// TXTSubtitleWriter 实现了 SubtitleWriter 接口，输出 "[hh:mm:ss] 说话人：文本" 形式的纯文本
+type TXTSubtitleWriter struct{}
+
+// txtCharDuration 用于估计最后一条（没有下一条可参照）的持续时间，约为每秒 4 个字
+const txtCharDuration = 250 * time.Millisecond
+
+const txtTimestampPattern = `((?:\d{1,2}:)?\d{1,3}:\d{2}(?:[.,]\d{1,3})?)`
+
+var (
+	txtTimestamp = regexp.MustCompile(`^(?:(\d{1,2}):)?(\d{1,3}):(\d{2})(?:[.,](\d{1,3}))?$`)
+	// 时间戳在行首，可以带括号，也可以是 "开始 - 结束" 的区间
+	txtBracketTime = regexp.MustCompile(`^[\[(（【]\s*` + txtTimestampPattern + `(?:\s*(?:-|–|~|-->)\s*` + txtTimestampPattern + `)?\s*[\])）】]\s*(.*)$`)
+	txtBareTime    = regexp.MustCompile(`^` + txtTimestampPattern + `(?:\s*(?:-|–|~|-->)\s*` + txtTimestampPattern + `)?(?:\s+(.*))?$`)
+	// 时间戳在行尾，前面是说话人："说话人 1 00:01:23"
+	txtHeaderTime = regexp.MustCompile(`^(.+?)\s+[\[(（【]?` + txtTimestampPattern + `[\])）】]?$`)
+	// 没有冒号的编号说话人："Speaker 1 文本"、"说话人2 文本"
+	txtNumberedSpeaker = regexp.MustCompile(`^((?i:speaker|spk|说话人|发言人|讲话人)\s*\d+)(?:\s*[：:]\s*|\s+|$)(.*)$`)
+	// 以冒号结束的说话人："主持人：文本"
+	txtColonSpeaker = regexp.MustCompile(`^([^：:]{1,20}?)\s*[：:]\s*(.*)$`)
+)
+
+// maxSpeakerLength 是说话人标签的最大字数，更长的内容视为正文
+const maxSpeakerLength = 20
+
+// txtParser 保存一次解析的状态
+type txtParser struct {
+	meta *Transcript
+	emit CueFunc
+
+	current     *Cue // 正在读取的条目，要等到下一条的开始时间才能确定结束时间
+	currentLine int
+	explicitEnd bool     // 当前条目的结束时间来自源文件
+	paragraph   []string // 尚未输出的无时间戳文本
+	count       int
+}
+
+// Parse 解析纯文本转写稿
+func (p *TXTSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	scanner := newLineScanner(r)
+	meta.Format = FormatTXT
+	parser := &txtParser{meta: meta, emit: emit}
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if err := parser.line(lineNo, strings.TrimSpace(scanner.Text())); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading subtitle file: %v", err)
+	}
+
+	if err := parser.flushParagraph(); err != nil {
+		return err
+	}
+	return parser.flush(-1)
+}
+
+// Write 将字幕写出为带时间戳的纯文本，每条一行；没有时间信息的条目写成单独的段落
+func (w *TXTSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	writer := bufio.NewWriter(out)
+	for _, cue := range transcript.Cues {
+		if cue.Has(FlagComment) {
+			continue
+		}
+		text := strings.ReplaceAll(cue.Text, "\n", " ")
+		if cue.Speaker != "" {
+			text = cue.Speaker + "：" + text
+		}
+		if cue.Has(FlagUntimed) {
+			fmt.Fprintf(writer, "%s\n\n", text)
+			continue
+		}
+		fmt.Fprintf(writer, "[%s] %s\n", formatClock(cue.Start, ".")[:8], text)
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing TXT: %v", err)
+	}
+	return nil
+}
+
+// line 处理一行内容
+func (p *txtParser) line(lineNo int, line string) error {
+	if start, end, hasEnd, speaker, text, ok := parseTXTLine(line); ok {
+		if err := p.flushParagraph(); err != nil {
+			return err
+		}
+		if err := p.flush(start); err != nil {
+			return err
+		}
+		p.current = &Cue{Start: start, End: end, Speaker: speaker, Text: text}
+		p.currentLine, p.explicitEnd = lineNo, hasEnd
+		if hasEnd && end < start {
+			p.warn(lineNo, "cue ends before it starts")
+		}
+		return nil
+	}
+
+	switch {
+	case line == "":
+		return p.flushParagraph()
+	case p.current != nil:
+		// 时间戳之后的文本行属于当前条目
+		if p.current.Text != "" {
+			p.current.Text += "\n"
+		}
+		p.current.Text += line
+	default:
+		p.paragraph = append(p.paragraph, line)
+	}
+	return nil
+}
+
+// flush 输出当前条目；next 是下一条的开始时间，文件结束时为 -1
+func (p *txtParser) flush(next time.Duration) error {
+	cue := p.current
+	p.current = nil
+	if cue == nil {
+		return nil
+	}
+	if cue.Text == "" {
+		p.warn(p.currentLine, "timestamp without text")
+		return nil
+	}
+	if !p.explicitEnd {
+		switch {
+		case next >= cue.Start:
+			cue.End = next
+		default:
+			if next >= 0 {
+				p.warn(p.currentLine, "timestamp is later than the next one")
+			}
+			cue.End = cue.Start + estimateDuration(cue.Text)
+		}
+	}
+	p.count++
+	cue.Index = p.count
+	return p.emit(*cue)
+}
+
+// flushParagraph 把积累的无时间戳文本作为一条没有时间信息的条目输出
+func (p *txtParser) flushParagraph() error {
+	if len(p.paragraph) == 0 {
+		return nil
+	}
+	text := strings.Join(p.paragraph, "\n")
+	p.paragraph = p.paragraph[:0]
+	p.count++
+	return p.emit(Cue{Index: p.count, Text: text, Flags: FlagUntimed})
+}
+
+// warn 记录一条警告
+func (p *txtParser) warn(lineNo int, format string, args ...interface{}) {
+	p.meta.Warnings = append(p.meta.Warnings, Warning{Line: lineNo, Message: fmt.Sprintf(format, args...)})
+}
+
+// parseTXTLine 识别以时间戳开头的行，或 "说话人 时间戳" 形式的标题行；
+// hasEnd 表示行中给出了结束时间
+func parseTXTLine(line string) (start, end time.Duration, hasEnd bool, speaker, text string, ok bool) {
+	var m []string
+	if m = txtBracketTime.FindStringSubmatch(line); m == nil {
+		m = txtBareTime.FindStringSubmatch(line)
+	}
+	if m != nil {
+		var err error
+		if start, err = parseTXTTimestamp(m[1]); err != nil {
+			return 0, 0, false, "", "", false
+		}
+		if m[2] != "" {
+			if end, err = parseTXTTimestamp(m[2]); err != nil {
+				return 0, 0, false, "", "", false
+			}
+			hasEnd = true
+		}
+		speaker, text = splitSpeaker(strings.TrimLeft(m[3], "-–—|:： "))
+		return start, end, hasEnd, speaker, text, true
+	}
+
+	if m = txtHeaderTime.FindStringSubmatch(line); m != nil && isSpeakerLabel(m[1]) {
+		var err error
+		if start, err = parseTXTTimestamp(m[2]); err == nil {
+			return start, 0, false, strings.TrimRight(m[1], "：: "), "", true
+		}
+	}
+	return 0, 0, false, "", "", false
+}
+
+// splitSpeaker 从文本开头分离出说话人标签，没有标签时 speaker 为空
+func splitSpeaker(text string) (string, string) {
+	if m := txtNumberedSpeaker.FindStringSubmatch(text); m != nil {
+		return m[1], m[2]
+	}
+	if m := txtColonSpeaker.FindStringSubmatch(text); m != nil && isSpeakerLabel(m[1]) && !strings.HasPrefix(m[2], "//") {
+		return m[1], m[2]
+	}
+	return "", text
+}
+
+// isSpeakerLabel 判断一段文字是否像说话人标签：不太长、不以数字开头、不含句中或句末标点
+func isSpeakerLabel(label string) bool {
+	label = strings.TrimRight(label, "：: ")
+	if label == "" || utf8.RuneCountInString(label) > maxSpeakerLength {
+		return false
+	}
+	if label[0] >= '0' && label[0] <= '9' {
+		return false
+	}
+	return !strings.ContainsAny(label, "，。,!?！？；;、")
+}
+
+// parseTXTTimestamp 解析 "hh:mm:ss"、"mm:ss" 形式的时间，可以带小数部分；
+// 没有小时时分钟数可以超过 59
+func parseTXTTimestamp(s string) (time.Duration, error) {
+	m := txtTimestamp.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	hours, _ := strconv.Atoi(m[1])
+	minutes, _ := strconv.Atoi(m[2])
+	seconds, _ := strconv.Atoi(m[3])
+	if seconds > 59 || (m[1] != "" && minutes > 59) {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	total := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	if frac := m[4]; frac != "" {
+		ms, _ := strconv.Atoi((frac + "00")[:3])
+		total += time.Duration(ms) * time.Millisecond
+	}
+	return total, nil
+}
+
+// estimateDuration 按字数估计一条字幕的持续时间，至少 1 秒
+func estimateDuration(text string) time.Duration {
+	d := time.Duration(utf8.RuneCountInString(text)) * txtCharDuration
+	if d < time.Second {
+		d = time.Second
+	}
+	return d
+}
+
+// txtTimedLines 返回 head 中以时间戳开头的行数和非空行数，最后一行可能被截断，不计入
+func txtTimedLines(head []byte) (timed, total int) {
+	lines := bytes.Split(head, []byte("\n"))
+	if len(lines) > 1 {
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		total++
+		if _, _, _, _, _, ok := parseTXTLine(string(line)); ok {
+			timed++
+		}
+	}
+	return timed, total
+}
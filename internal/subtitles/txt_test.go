@@ -0,0 +1,108 @@
+package subtitles
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestTXTParserMeeting tests inline timestamps, speaker labels, header lines and the untimed preamble.
+func TestTXTParserMeeting(t *testing.T) {
+	file, err := os.Open("testdata/meeting.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	transcript, err := ParseAll(&TXTSubtitleParser{}, file)
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if transcript.Format != FormatTXT {
+		t.Errorf("Format = %q, want %q", transcript.Format, FormatTXT)
+	}
+	want := []Cue{
+		{Index: 1, Text: "周会纪要\n2024 年 3 月 1 日", Flags: FlagUntimed},
+		{Index: 2, Start: 5 * time.Second, End: 12 * time.Second, Speaker: "主持人", Text: "大家好，我们开始吧"},
+		{Index: 3, Start: 12 * time.Second, End: 63 * time.Second, Speaker: "张三", Text: "上周的版本已经发布了\n后面还有两个小问题"},
+		{Index: 4, Start: 63 * time.Second, End: 70 * time.Second, Speaker: "主持人", Text: "好的"},
+		{Index: 5, Start: 90 * time.Second, End: 91250 * time.Millisecond, Speaker: "说话人 2", Text: "我补充一下"},
+	}
+	if !reflect.DeepEqual(transcript.Cues, want) {
+		t.Errorf("got %+v\nwant %+v", transcript.Cues, want)
+	}
+	if len(transcript.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", transcript.Warnings)
+	}
+}
+
+// TestTXTParserLayouts tests the speaker label heuristics on single lines.
+func TestTXTParserLayouts(t *testing.T) {
+	tests := []struct {
+		line    string
+		start   time.Duration
+		speaker string
+		text    string
+	}{
+		{"00:01 Speaker 1 hello there", time.Second, "Speaker 1", "hello there"},
+		{"（00:02:03）说话人1：你好", 2*time.Minute + 3*time.Second, "说话人1", "你好"},
+		{"75:00 - Alice: long meeting", 75 * time.Minute, "Alice", "long meeting"},
+		{"[00:00:01.5] 见 https://example.com", 1500 * time.Millisecond, "", "见 https://example.com"},
+		{"[00:00:02] 我觉得，其实：不一定", 2 * time.Second, "", "我觉得，其实：不一定"},
+	}
+	for _, tt := range tests {
+		start, _, _, speaker, text, ok := parseTXTLine(tt.line)
+		if !ok || start != tt.start || speaker != tt.speaker || text != tt.text {
+			t.Errorf("%q: got (%v, %q, %q, %v), want (%v, %q, %q)", tt.line, start, speaker, text, ok, tt.start, tt.speaker, tt.text)
+		}
+	}
+
+	for _, line := range []string{"12:30pm 开会", "会议的时间是下午，大约 3:00", "第 1 章"} {
+		if _, _, _, _, _, ok := parseTXTLine(line); ok {
+			t.Errorf("%q: recognised as a timestamp line", line)
+		}
+	}
+}
+
+// TestTXTParserProse tests that untimed text is split into paragraphs at blank lines.
+func TestTXTParserProse(t *testing.T) {
+	data := "第一段第一行\n第一段第二行\n\n\n第二段\n"
+	transcript, err := ParseAll(&TXTSubtitleParser{}, strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	want := []Cue{
+		{Index: 1, Text: "第一段第一行\n第一段第二行", Flags: FlagUntimed},
+		{Index: 2, Text: "第二段", Flags: FlagUntimed},
+	}
+	if !reflect.DeepEqual(transcript.Cues, want) {
+		t.Errorf("got %+v\nwant %+v", transcript.Cues, want)
+	}
+}
+
+// TestTXTRoundTrip tests that written transcripts parse back to the same starts and speakers.
+func TestTXTRoundTrip(t *testing.T) {
+	original := &Transcript{Cues: []Cue{
+		{Index: 1, Start: time.Second, End: 3 * time.Second, Speaker: "主持人", Text: "你好"},
+		{Index: 2, Start: 3 * time.Second, End: 4 * time.Second, Text: "没有说话人"},
+	}}
+	var out strings.Builder
+	if err := (&TXTSubtitleWriter{}).Write(&out, original); err != nil {
+		t.Fatal(err)
+	}
+	if want := "[00:00:01] 主持人：你好\n[00:00:03] 没有说话人\n"; out.String() != want {
+		t.Errorf("wrote %q, want %q", out.String(), want)
+	}
+
+	parsed, err := ParseAll(&TXTSubtitleParser{}, strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, cue := range parsed.Cues {
+		if cue.Start != original.Cues[i].Start || cue.Speaker != original.Cues[i].Speaker || cue.Text != original.Cues[i].Text {
+			t.Errorf("cue %d = %+v, want %+v", i+1, cue, original.Cues[i])
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package subtitles
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VTTSubtitleParser 实现了 SubtitleParser 接口，专门处理 WebVTT 格式
+type VTTSubtitleParser struct{}
+
+// VTTSubtitleWriter 实现了 SubtitleWriter 接口，输出 WebVTT 格式
+type VTTSubtitleWriter struct{}
+
+// Parse 解析 WebVTT 格式字幕文件，每读完一个块就输出对应的条目
+func (p *VTTSubtitleParser) Parse(r io.Reader, meta *Transcript, emit CueFunc) error {
+	meta.Format = FormatVTT
+	count := 0
+	header := true
+
+	err := scanBlocks(r, func(block []string) error {
+		if header {
+			header = false
+			if !isVTTHeader(block[0]) {
+				return fmt.Errorf("missing WEBVTT header")
+			}
+			// 头部块中可能带有 "Language: zh" 之类的元数据
+			for _, line := range block[1:] {
+				if key, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(key), "Language") {
+					meta.Lang = strings.TrimSpace(value)
+				}
+			}
+			return nil
+		}
+
+		// 全局 ::cue 样式中的颜色可以还原为 BCC 显示设置，其余样式、注释和区域定义块都跳过
+		if isVTTKeywordBlock(block[0], "STYLE") {
+			parseVTTCueStyle(block[1:], meta)
+			return nil
+		}
+		if isVTTKeywordBlock(block[0], "NOTE") || isVTTKeywordBlock(block[0], "REGION") {
+			return nil
+		}
+
+		// 时间行之前的行是可选的条目标识
+		timing := -1
+		for i, line := range block {
+			if strings.Contains(line, "-->") {
+				timing = i
+				break
+			}
+		}
+		if timing < 0 || timing > 1 {
+			return nil
+		}
+
+		cue := Cue{Index: count + 1}
+		if timing == 1 {
+			if n, err := strconv.Atoi(block[0]); err == nil {
+				cue.Index = n
+			}
+		}
+
+		start, end, settings, err := parseVTTTiming(block[timing])
+		if err != nil {
+			return fmt.Errorf("error parsing cue timing: %v", err)
+		}
+		cue.Start, cue.End, cue.Settings = start, end, settings
+
+		var lines []string
+		for _, line := range block[timing+1:] {
+			text, speaker := stripVTTTags(line)
+			if cue.Speaker == "" {
+				cue.Speaker = speaker
+			}
+			if text = strings.TrimSpace(text); text != "" {
+				lines = append(lines, text)
+			}
+		}
+		cue.Text = strings.Join(lines, "\n")
+		if cue.Text == "" {
+			return nil
+		}
+		count++
+		return emit(cue)
+	})
+	if err != nil {
+		return err
+	}
+	if header {
+		return fmt.Errorf("missing WEBVTT header")
+	}
+	return nil
+}
+
+// Write 将字幕写出为 WebVTT 格式，注释行不会写出
+func (w *VTTSubtitleWriter) Write(out io.Writer, transcript *Transcript) error {
+	writer := bufio.NewWriter(out)
+
+	writer.WriteString("WEBVTT\n")
+	if transcript.Lang != "" {
+		fmt.Fprintf(writer, "Language: %s\n", transcript.Lang)
+	}
+	if transcript.BCC != nil {
+		writeVTTCueStyle(writer, transcript.BCC)
+	}
+
+	for i, cue := range transcript.Cues {
+		if cue.Has(FlagComment) {
+			continue
+		}
+		index := cue.Index
+		if index == 0 {
+			index = i + 1
+		}
+		fmt.Fprintf(writer, "\n%d\n%s --> %s", index, formatClock(cue.Start, "."), formatClock(cue.End, "."))
+		if cue.Settings != "" {
+			writer.WriteString(" " + cue.Settings)
+		}
+		writer.WriteString("\n")
+
+		text := escapeVTTText(cue.Text)
+		if cue.Speaker != "" {
+			text = "<v " + escapeVTTText(cue.Speaker) + ">" + text
+		}
+		writer.WriteString(text + "\n")
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("error writing WebVTT: %v", err)
+	}
+	return nil
+}
+
+var (
+	vttGlobalCueRule = regexp.MustCompile(`::cue\s*\{([^}]*)\}`)
+	vttRGBA          = regexp.MustCompile(`^rgba\(\s*(\d+)\s*,\s*(\d+)\s*,\s*(\d+)\s*,\s*([\d.]+)\s*\)$`)
+)
+
+// writeVTTCueStyle 用全局 ::cue 样式写出 BCC 的文字颜色和背景
+func writeVTTCueStyle(writer *bufio.Writer, meta *BCCMeta) {
+	var rules []string
+	if meta.FontColor != "" {
+		rules = append(rules, "color: "+meta.FontColor+";")
+	}
+	if colour := strings.TrimPrefix(meta.BackgroundColor, "#"); len(colour) == 6 {
+		if rgb, err := strconv.ParseUint(colour, 16, 32); err == nil {
+			rules = append(rules, fmt.Sprintf("background-color: rgba(%d, %d, %d, %s);",
+				rgb>>16, rgb>>8&0xFF, rgb&0xFF, strconv.FormatFloat(meta.BackgroundAlpha, 'f', -1, 64)))
+		}
+	}
+	if len(rules) == 0 {
+		return
+	}
+	fmt.Fprintf(writer, "\nSTYLE\n::cue {\n  %s\n}\n", strings.Join(rules, "\n  "))
+}
+
+// parseVTTCueStyle 从全局 ::cue 样式中读取文字颜色和背景；带选择器的 ::cue(...) 样式会被忽略
+func parseVTTCueStyle(lines []string, meta *Transcript) {
+	m := vttGlobalCueRule.FindStringSubmatch(strings.Join(lines, "\n"))
+	if m == nil {
+		return
+	}
+	for _, declaration := range strings.Split(m[1], ";") {
+		name, value, ok := strings.Cut(declaration, ":")
+		if !ok {
+			continue
+		}
+		name, value = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(value)
+		switch name {
+		case "color":
+			if strings.HasPrefix(value, "#") {
+				bccMeta(meta).FontColor = strings.ToUpper(value)
+			}
+		case "background-color":
+			if rgba := vttRGBA.FindStringSubmatch(value); rgba != nil {
+				r, _ := strconv.Atoi(rgba[1])
+				g, _ := strconv.Atoi(rgba[2])
+				b, _ := strconv.Atoi(rgba[3])
+				alpha, _ := strconv.ParseFloat(rgba[4], 64)
+				bcc := bccMeta(meta)
+				bcc.BackgroundColor = fmt.Sprintf("#%02X%02X%02X", r, g, b)
+				bcc.BackgroundAlpha = alpha
+			}
+		}
+	}
+}
+
+// bccMeta 返回 meta.BCC，为 nil 时先按 B 站默认值创建
+func bccMeta(meta *Transcript) *BCCMeta {
+	if meta.BCC == nil {
+		bcc := defaultBCCMeta
+		meta.BCC = &bcc
+	}
+	return meta.BCC
+}
+
+// scanBlocks 按空行把内容切分为若干块，每得到一块就交给 fn
+func scanBlocks(r io.Reader, fn func(block []string) error) error {
+	scanner := newLineScanner(r)
+	var block []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r \t")
+		if line == "" {
+			if len(block) > 0 {
+				if err := fn(block); err != nil {
+					return err
+				}
+				block = nil
+			}
+			continue
+		}
+		block = append(block, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading subtitle file: %v", err)
+	}
+	if len(block) > 0 {
+		return fn(block)
+	}
+	return nil
+}
+
+// isVTTHeader 判断是否为 "WEBVTT" 文件头
+func isVTTHeader(line string) bool {
+	return isVTTKeywordBlock(line, "WEBVTT")
+}
+
+// isVTTKeywordBlock 判断一行是否以关键字开头，且关键字后面是空白或行尾
+func isVTTKeywordBlock(line, keyword string) bool {
+	if !strings.HasPrefix(line, keyword) {
+		return false
+	}
+	rest := line[len(keyword):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// parseVTTTiming 解析 "00:01.000 --> 00:02.000 align:start" 形式的时间行
+func parseVTTTiming(line string) (time.Duration, time.Duration, string, error) {
+	left, right, _ := strings.Cut(line, "-->")
+	start, err := parseVTTTimestamp(left)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	fields := strings.Fields(right)
+	if len(fields) == 0 {
+		return 0, 0, "", fmt.Errorf("missing end time in %q", line)
+	}
+	end, err := parseVTTTimestamp(fields[0])
+	if err != nil {
+		return 0, 0, "", err
+	}
+	return start, end, strings.Join(fields[1:], " "), nil
+}
+
+// parseVTTTimestamp 解析 "mm:ss.ttt" 或 "hh:mm:ss.ttt"
+func parseVTTTimestamp(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	clock, frac, ok := strings.Cut(s, ".")
+	if !ok || len(frac) != 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	fields := strings.Split(clock, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var total time.Duration
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		total = total*60 + time.Duration(n)*time.Second
+	}
+	ms, err := strconv.Atoi(frac)
+	if err != nil || ms < 0 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	return total + time.Duration(ms)*time.Millisecond, nil
+}
+
+// stripVTTTags 去掉 <c>、<i>、<v> 以及内联时间戳等标签，并返回 <v> 标签中的说话人
+func stripVTTTags(line string) (string, string) {
+	var text strings.Builder
+	speaker := ""
+	for {
+		open := strings.IndexByte(line, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(line[open:], '>')
+		if end < 0 {
+			break
+		}
+		text.WriteString(line[:open])
+		tag := line[open+1 : open+end]
+		line = line[open+end+1:]
+
+		// <v Speaker> 或 <v.class Speaker>
+		if speaker == "" && len(tag) > 1 && tag[0] == 'v' && (tag[1] == ' ' || tag[1] == '.' || tag[1] == '\t') {
+			if _, name, ok := strings.Cut(tag, " "); ok {
+				speaker = html.UnescapeString(strings.TrimSpace(name))
+			}
+		}
+	}
+	text.WriteString(line)
+	return html.UnescapeString(text.String()), speaker
+}
+
+// escapeVTTText 转义 WebVTT 文本中的特殊字符
+func escapeVTTText(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,76 @@
+package subtitles
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestVTTParserSample tests header metadata, NOTE/STYLE blocks, voice tags and inline tags.
+func TestVTTParserSample(t *testing.T) {
+	transcript := parseVTTFile(t, "testdata/sample.vtt")
+
+	if transcript.Lang != "zh-CN" {
+		t.Errorf("Lang = %q, want zh-CN", transcript.Lang)
+	}
+	want := []Cue{
+		{Index: 1, Start: 500 * time.Millisecond, End: 3200 * time.Millisecond, Text: "欢迎来到 本期 节目", Speaker: "主持人", Settings: "align:start position:10%"},
+		{Index: 2, Start: 3200 * time.Millisecond, End: 6 * time.Second, Text: "大家好，我是 小明\n很高兴来到这里", Speaker: "嘉宾"},
+		{Index: 3, Start: 6 * time.Second, End: 9500 * time.Millisecond, Text: "卡拉OK & 字幕 <测试>"},
+	}
+	if !reflect.DeepEqual(transcript.Cues, want) {
+		t.Errorf("cues = %+v\nwant %+v", transcript.Cues, want)
+	}
+}
+
+// TestVTTRoundTrip tests that parsing the written output of each sample yields the same cues.
+func TestVTTRoundTrip(t *testing.T) {
+	for _, path := range []string{"testdata/sample.vtt", "testdata/simple.vtt"} {
+		original := parseVTTFile(t, path)
+
+		var buf bytes.Buffer
+		if err := (&VTTSubtitleWriter{}).Write(&buf, original); err != nil {
+			t.Fatalf("%s: Write returned error: %v", path, err)
+		}
+		reparsed, err := ParseAll(&VTTSubtitleParser{}, &buf)
+		if err != nil {
+			t.Fatalf("%s: reparse returned error: %v", path, err)
+		}
+		if !reflect.DeepEqual(original, reparsed) {
+			t.Errorf("%s: round trip mismatch\n got %+v\nwant %+v", path, reparsed, original)
+		}
+	}
+}
+
+// TestVTTWriterCanonical tests that a file already in canonical form is written back unchanged.
+func TestVTTWriterCanonical(t *testing.T) {
+	data, err := os.ReadFile("testdata/simple.vtt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	transcript := parseVTTFile(t, "testdata/simple.vtt")
+
+	var buf bytes.Buffer
+	if err := (&VTTSubtitleWriter{}).Write(&buf, transcript); err != nil {
+		t.Fatalf("Write returned error: %v", err)
+	}
+	if buf.String() != string(data) {
+		t.Errorf("written output differs:\n%s\nwant:\n%s", buf.String(), data)
+	}
+}
+
+func parseVTTFile(t *testing.T, path string) *Transcript {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	transcript, err := ParseAll(&VTTSubtitleParser{}, file)
+	if err != nil {
+		t.Fatalf("%s: Parse returned error: %v", path, err)
+	}
+	return transcript
+}
@@ -1,15 +1,41 @@
 package summarization
 
 import (
+	"bilibili_subtitle/internal/metadata"
+	"bilibili_subtitle/internal/stats"
+	"bilibili_subtitle/internal/zhconv"
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
+// SaveOptions 是保存结果时的可选设置
+type SaveOptions struct {
+	Chinese  string                  // 对生成文本做简繁转换的配置（见 zhconv 包），为空时不转换
+	Stats    *stats.Report           // 字幕统计数据，不为空时写入 analysis.md 并另存为 stats.json
+	Metadata *metadata.VideoMetadata // 视频信息，不为空时作为 front matter 写在 analysis.md 开头
+}
+
 // SaveSubtitleToFile 保存原始文本和生成文本到指定文件
 func SaveSubtitleToFile(filePath, parsedText, result string) error {
+	return SaveSubtitleToFileWithOptions(filePath, parsedText, result, SaveOptions{})
+}
+
+// SaveSubtitleToFileWithOptions 与 SaveSubtitleToFile 相同，opts 控制额外的处理和输出：
+// Chinese 在保存前对生成文本做简繁转换，Stats 写入 analysis.md 并另存为 stats.json，
+// Metadata 作为 front matter 写在 analysis.md 开头
+func SaveSubtitleToFileWithOptions(filePath, parsedText, result string, opts SaveOptions) error {
+	if opts.Chinese != "" {
+		converter, err := zhconv.New(opts.Chinese)
+		if err != nil {
+			return err
+		}
+		result = converter.Convert(result)
+	}
+
 	// 获取文件名和文件后缀
 	fileName := filepath.Base(filePath)
 	ext := filepath.Ext(fileName)
@@ -17,10 +43,12 @@ func SaveSubtitleToFile(filePath, parsedText, result string) error {
 	// 生成文件名
 	originalFileName := strings.TrimSuffix(fileName, ext) + "original.md"
 	analysisResultFileName := strings.TrimSuffix(fileName, ext) + "analysis.md"
+	statsFileName := strings.TrimSuffix(fileName, ext) + "stats.json"
 
 	// 生成文件路径
 	originalFilePath := filepath.Join(filepath.Dir(filePath), originalFileName)
 	analysisResultFilePath := filepath.Join(filepath.Dir(filePath), analysisResultFileName)
+	statsFilePath := filepath.Join(filepath.Dir(filePath), statsFileName)
 
 	// 写入原始文本到 original.md 文件
 	err := writeTextToFile(originalFilePath, parsedText)
@@ -29,11 +57,23 @@ func SaveSubtitleToFile(filePath, parsedText, result string) error {
 	}
 
 	// 写入原始文本和生成文本到 analysis.md 文件
-	err = writeAnalysisToFile(analysisResultFilePath, parsedText, result)
+	err = writeAnalysisToFile(analysisResultFilePath, parsedText, result, opts.Stats, opts.Metadata)
 	if err != nil {
 		return fmt.Errorf("error writing analysis result to file %s: %w", analysisResultFilePath, err)
 	}
 
+	// 写入统计数据到 stats.json 文件
+	if opts.Stats != nil {
+		data, err := json.MarshalIndent(opts.Stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error encoding statistics: %w", err)
+		}
+		err = writeTextToFile(statsFilePath, string(data)+"\n")
+		if err != nil {
+			return fmt.Errorf("error writing statistics to file %s: %w", statsFilePath, err)
+		}
+	}
+
 	return nil
 }
 
@@ -64,8 +104,8 @@ func writeTextToFile(filePath, text string) error {
 	return nil
 }
 
-// writeAnalysisToFile 写入分析结果文件
-func writeAnalysisToFile(filePath, parsedText, result string) error {
+// writeAnalysisToFile 写入分析结果文件，meta 不为空时在开头写入 front matter，report 不为空时在末尾追加统计数据
+func writeAnalysisToFile(filePath, parsedText, result string, report *stats.Report, meta *metadata.VideoMetadata) error {
 	// 创建并打开文件，如果文件已存在则覆盖
 	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
 	if err != nil {
@@ -77,6 +117,14 @@ func writeAnalysisToFile(filePath, parsedText, result string) error {
 	writer := bufio.NewWriter(file)
 	defer writer.Flush()
 
+	// 写入视频信息
+	if meta != nil {
+		_, err = writer.WriteString(meta.FrontMatter())
+		if err != nil {
+			return fmt.Errorf("error writing front matter to file %s: %w", filePath, err)
+		}
+	}
+
 	// 写入原始文本标题和内容
 	_, err = writer.WriteString("## 原始文本：\n\n")
 	if err != nil {
@@ -100,6 +148,14 @@ func writeAnalysisToFile(filePath, parsedText, result string) error {
 		return fmt.Errorf("error writing generated text to file %s: %w", filePath, err)
 	}
 
+	// 写入统计数据
+	if report != nil {
+		_, err = writer.WriteString("\n\n## 统计数据：\n\n" + report.Markdown())
+		if err != nil {
+			return fmt.Errorf("error writing statistics to file %s: %w", filePath, err)
+		}
+	}
+
 	// 确保所有缓冲区的内容被写入文件
 	if err := writer.Flush(); err != nil {
 		return fmt.Errorf("error flushing file %s: %w", filePath, err)
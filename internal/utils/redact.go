@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"io"
+	"strings"
+)
+
+// Redacted 是日志和配置输出中代替密钥的占位符
+const Redacted = "[REDACTED]"
+
+// Redact 把非空的密钥替换为 Redacted，空字符串原样返回，便于看出密钥是否已配置
+func Redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// RedactWriter 把写入内容中的密钥替换为 Redacted 后再写入 W，用于 log.SetOutput，
+// 防止 SESSDATA 等凭据出现在日志中
+type RedactWriter struct {
+	W        io.Writer
+	replacer *strings.Replacer
+}
+
+// NewRedactWriter 创建隐藏 secrets 的 RedactWriter，空字符串会被忽略
+func NewRedactWriter(w io.Writer, secrets ...string) *RedactWriter {
+	var pairs []string
+	for _, secret := range secrets {
+		if secret != "" {
+			pairs = append(pairs, secret, Redacted)
+		}
+	}
+	return &RedactWriter{W: w, replacer: strings.NewReplacer(pairs...)}
+}
+
+// Write 写入隐藏密钥后的内容；log 包每条日志只调用一次 Write，因此密钥不会被拆开
+func (r *RedactWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(r.W, r.replacer.Replace(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,93 @@
+package zhconv
+
+// charPairs 是繁简字对照表，每项为 "繁简" 两个字，以空白分隔。
+// 一个简体字对应多个繁体字时，排在前面的繁体字是简转繁的默认结果，
+// 其余用法由词组表处理
+const charPairs = `
+計计 訂订 訃讣 認认 譏讥 討讨 讓让 訕讪 訖讫 訓训 議议 訊讯 記记 講讲 諱讳 謳讴 詎讵 訝讶 訥讷 許许 訛讹 論论 訟讼 諷讽
+設设 訪访 訣诀 證证 詁诂 訶诃 評评 詛诅 識识 詐诈 訴诉 診诊 詆诋 謅诌 詞词 詘诎 詔诏 譯译 詒诒 誆诓 誄诔 試试 詿诖 詩诗
+詰诘 詼诙 誠诚 誅诛 話话 誕诞 詬诟 詮诠 詭诡 詢询 詣诣 諍诤 該该 詳详 詫诧 諢诨 詡诩 誡诫 誣诬 語语 誚诮 誤误 誥诰 誘诱
+誨诲 誑诳 說说 説说 誦诵 請请 諸诸 諏诹 諾诺 讀读 諑诼 誹诽 課课 諉诿 諛谀 誰谁 調调 諂谄 諒谅 諄谆 談谈 誼谊 謀谋 諜谍
+謊谎 諫谏 諧谐 謔谑 謁谒 謂谓 諤谔 諭谕 諼谖 讒谗 諮谘 諳谙 諺谚 諦谛 謎谜 諞谝 謨谟 讜谠 謝谢 謠谣 謗谤 謙谦 謐谧 謹谨
+謾谩 謫谪 謬谬 譚谭 譖谮 譙谯 讕谰 譜谱 譎谲 讞谳 譴谴 譫谵 讖谶 變变 訌讧 訐讦 譽誉 讚赞 釓钆 釔钇 針针 釘钉 釗钊 釙钋
+釕钌 釷钍 釧钏 釤钐 釩钒 釣钓 釹钕 鈣钙 鈈钚 鈦钛 鈍钝 鈔钞 鐘钟 鍾钟 鈉钠 鋇钡 鋼钢 鈑钣 鈐钤 鑰钥 欽钦 鈞钧 鎢钨 鈧钪
+鈁钫 鈥钬 鈄钭 鈕钮 鈀钯 鈺钰 錢钱 鉦钲 鉗钳 鈷钴 缽钵 鉢钵 鈳钶 鈽钸 鈸钹 鉞钺 鑽钻 鉬钼 鉭钽 鉀钾 鈿钿 鈾铀 鐵铁 鉑铂
+鈴铃 鑠铄 鉛铅 鉚铆 鈰铈 鉉铉 鉈铊 鉍铋 鈮铌 鈹铍 鐸铎 銬铐 銠铑 鉺铒 銪铕 鋮铖 鋏铗 鐃铙 鐺铛 銅铜 鋁铝 銦铟 鎧铠 銖铢
+銑铣 鋌铤 銩铥 鏵铧 銓铨 鉿铪 鎩铩 銚铫 鉻铬 銘铭 錚铮 銫铯 鉸铰 銥铱 銃铳 銨铵 銀银 銣铷 鑄铸 鋪铺 舖铺 鏈链 鋒锋 鋅锌
+銳锐 鋭锐 銷销 鎖锁 鋤锄 鍋锅 鏽锈 銹锈 鋥锃 鋰锂 鋯锆 錯错 錫锡 鑼锣 錘锤 錐锥 錦锦 鍵键 鋸锯 錳锰 錸铼 鍍镀 鎂镁 鏤镂
+鐮镰 鏡镜 鏢镖 鏟铲 剷铲 鑒鉴 鑑鉴 鍛锻 鎮镇 鏃镞 鏇镟 鐫镌 鐳镭 鑲镶 鑷镊 錄录 鑿凿 鍬锹 鍘铡 鉤钩 鈎钩 鉅钜 鎊镑 錨锚
+錠锭 鍁锨 鍥锲 鎬镐 鐲镯 鐐镣 鐙镫 銜衔 鑣镳 鎳镍 鍺锗 銻锑 釺钎 鍼针 糾纠 紆纡 紅红 紂纣 纖纤 縴纤 紇纥 約约 級级 紈纨
+紀纪 紉纫 緯纬 紜纭 純纯 紕纰 紗纱 綱纲 納纳 縱纵 綸纶 紛纷 紙纸 紋纹 紡纺 紐纽 紓纾 線线 綫线 紺绀 紲绁 紱绂 練练 組组
+紳绅 細细 織织 終终 縐绉 絆绊 紼绋 絀绌 紹绍 繹绎 經经 紿绐 綁绑 絨绒 結结 絝绔 繞绕 絎绗 繪绘 給给 絢绚 絳绛 絡络 絕绝
+絞绞 統统 綆绠 綃绡 絹绢 繡绣 綏绥 絛绦 繼继 綈绨 績绩 緒绪 綾绫 續续 綺绮 緋绯 綽绰 緄绲 繩绳 維维 綿绵 綬绶 繃绷 綢绸
+綹绺 綣绻 綜综 綻绽 綰绾 綠绿 綴缀 緇缁 緙缂 緗缃 緘缄 緬缅 纜缆 緹缇 緲缈 緝缉 繢缋 緦缌 緞缎 緱缑 縋缒 緩缓 締缔 縷缕
+編编 緡缗 緣缘 縉缙 縛缚 縟缛 縝缜 縫缝 縞缟 纏缠 縭缡 縊缢 縑缣 繽缤 縹缥 縵缦 縲缧 纓缨 縮缩 繆缪 繅缫 纈缬 繚缭 繕缮
+繒缯 韁缰 繾缱 繯缳 纘缵 罌罂 絲丝 緊紧 縣县 縈萦 縶絷 貝贝 貞贞 負负 財财 貢贡 貧贫 貨货 販贩 貪贪 貫贯 責责 貯贮 貰贳
+貲赀 貳贰 貴贵 貶贬 買买 貸贷 貺贶 費费 貼贴 貽贻 貿贸 賀贺 賁贲 賂赂 賃赁 賄贿 賅赅 資资 賈贾 賊贼 賑赈 賒赊 賓宾 賕赇
+賜赐 賞赏 賦赋 賤贱 賬账 賭赌 賢贤 賣卖 賠赔 質质 賡赓 賴赖 賺赚 賻赙 購购 賽赛 贅赘 贄贽 贈赠 贊赞 贍赡 贏赢 贐赆 贓赃
+贖赎 贗赝 實实 則则 側侧 測测 廁厕 惻恻 敗败 償偿 頁页 頂顶 頃顷 項项 順顺 須须 鬚须 頊顼 頑顽 顧顾 頓顿 頎颀 頒颁 頌颂
+頏颃 預预 領领 頗颇 頸颈 頡颉 頰颊 頜颌 潁颍 頤颐 頻频 頭头 顆颗 題题 額额 顎颚 顏颜 顓颛 願愿 顛颠 類类 顙颡 顢颟 顫颤
+顯显 顱颅 顴颧 頹颓 穎颖 馬马 馭驭 馮冯 馱驮 馳驰 馴驯 駁驳 驢驴 駛驶 駟驷 駙驸 駒驹 駐驻 駝驼 駑驽 駕驾 驛驿 駘骀 驍骁
+駭骇 駢骈 驊骅 騎骑 駿骏 驗验 騙骗 騷骚 驅驱 驃骠 驕骄 驚惊 騰腾 驟骤 驥骥 驤骧 騾骡 罵骂 篤笃 媽妈 嗎吗 瑪玛 碼码 螞蚂
+騁骋 駱骆 魚鱼 魯鲁 鮑鲍 鮒鲋 鮮鲜 鯉鲤 鯊鲨 鯽鲫 鯨鲸 鰓鳃 鰭鳍 鱷鳄 鱗鳞 鱔鳝 鰻鳗 鱈鳕 鱖鳜 鯛鲷 鮭鲑 鰱鲢 鱅鳙 鯿鳊
+鮪鲔 鱘鲟 漁渔 鳥鸟 鳩鸠 鳳凤 鳴鸣 鳶鸢 鴉鸦 鴨鸭 鴕鸵 鴛鸳 鴦鸯 鴣鸪 鴻鸿 鵝鹅 鵑鹃 鵡鹉 鵲鹊 鵬鹏 鶯莺 鶴鹤 鷹鹰 鷗鸥
+鷺鹭 鸚鹦 鸞鸾 鴿鸽 鶉鹑 雞鸡 鷄鸡 鶩鹜 鷲鹫 鷓鹧 鸛鹳 鷂鹞 鶚鹗 鸕鸬 鷸鹬 門门 閂闩 閃闪 問问 闖闯 閉闭 閏闰 閒闲 閑闲
+開开 間间 閔闵 閘闸 閡阂 閣阁 閥阀 閨闺 聞闻 閩闽 閭闾 閱阅 閲阅 閻阎 閹阉 閶阊 閾阈 閿阌 闊阔 闋阕 闌阑 闈闱 闐阗 闔阖
+闕阙 闡阐 關关 闥闼 悶闷 憫悯 車车 軋轧 軌轨 軍军 軒轩 軔轫 軟软 轉转 軛轭 輪轮 軸轴 軼轶 軻轲 軫轸 軾轼 較较 輊轾 輅辂
+載载 輕轻 輒辄 輔辅 輛辆 輦辇 輝辉 輩辈 輟辍 輥辊 輞辋 輜辎 輯辑 輸输 輻辐 輾辗 輿舆 轄辖 轅辕 轆辘 轍辙 轎轿 轟轰 轡辔
+轢轹 陣阵 連连 蓮莲 漣涟 璉琏 塹堑 暫暂 斬斩 漸渐 慚惭 嶄崭 見见 規规 覓觅 視视 覘觇 覽览 覺觉 覬觊 覡觋 覦觎 親亲 覲觐
+觀观 靚靓 攪搅 風风 颯飒 颶飓 颼飕 飄飘 飆飙 飛飞 飢饥 饑饥 飣饤 飪饪 飫饫 飭饬 飯饭 飲饮 餞饯 飾饰 飽饱 飼饲 飴饴 餌饵
+饒饶 蝕蚀 餃饺 餅饼 餉饷 養养 餓饿 餒馁 餘余 餚肴 館馆 饞馋 饋馈 饅馒 饗飨 饜餍 餿馊 饃馍 餡馅 餛馄 餾馏 長长 張张 帳帐
+脹胀 漲涨 韋韦 韌韧 韓韩 韙韪 韜韬 偉伟 違违 圍围 衛卫 葦苇 齒齿 齡龄 齦龈 齜龇 齷龌 齪龊 齲龋 齧啮 龍龙 龐庞 龔龚 龕龛
+壟垄 籠笼 聾聋 瀧泷 嚨咙 朧胧 攏拢 隴陇 寵宠 龜龟 黽黾 蠅蝇 麥麦 麩麸 鹵卤 滷卤 鹹咸 鹼碱 鹽盐 東东 凍冻 棟栋 陳陈 樂乐
+礫砾 爍烁 爲为 為为 僞伪 偽伪 書书 專专 傳传 磚砖 堯尧 撓挠 燒烧 曉晓 蹺跷 僥侥 澆浇 翹翘 區区 嘔呕 歐欧 毆殴 樞枢 軀躯
+甌瓯 漚沤 歲岁 會会 燴烩 檜桧 薈荟 儈侩 劊刽 邊边 過过 還还 環环 這这 進进 遠远 運运 達达 遷迁 遲迟 選选 適适 遞递 遺遗
+鄧邓 鄭郑 郵邮 鄉乡 響响 麼么 麽么 義义 儀仪 蟻蚁 習习 寫写 瀉泻 農农 濃浓 膿脓 儂侬 對对 導导 壽寿 濤涛 禱祷 籌筹 疇畴
+躊踌 將将 蔣蒋 漿浆 獎奖 槳桨 醬酱 爾尔 彌弥 瀰弥 邇迩 塵尘 嘗尝 嚐尝 層层 屜屉 豈岂 凱凯 愷恺 島岛 嶺岭 幣币 師师 獅狮
+篩筛 帶带 滯滞 幫帮 幹干 乾干 莊庄 慶庆 庫库 應应 廟庙 廢废 異异 棄弃 彈弹 強强 歸归 當当 噹当 擋挡 檔档 黨党 徹彻 憶忆
+憂忧 優优 擾扰 懷怀 壞坏 態态 憐怜 總总 戀恋 懇恳 惡恶 噁恶 惱恼 腦脑 懸悬 懼惧 慘惨 慣惯 憤愤 戲戏 戰战 戶户 執执 擴扩
+掃扫 揚扬 楊杨 陽阳 湯汤 場场 腸肠 暢畅 燙烫 蕩荡 盪荡 瘍疡 撫抚 拋抛 搶抢 槍枪 倉仓 蒼苍 艙舱 創创 瘡疮 滄沧 嗆呛 護护
+報报 擔担 膽胆 擬拟 擁拥 擇择 澤泽 掛挂 擠挤 濟济 齊齐 劑剂 臍脐 揮挥 渾浑 損损 撿捡 檢检 儉俭 險险 劍剑 簽签 籤签 臉脸
+斂敛 殮殓 換换 喚唤 煥焕 渙涣 瘓痪 據据 擲掷 攬揽 攙搀 攝摄 懾慑 躡蹑 聶聂 擺摆 罷罢 襬摆 搖摇 撐撑 敵敌 數数 齋斋 鬥斗
+鬪斗 無无 舊旧 時时 曠旷 礦矿 晝昼 曬晒 暈晕 術术 殺杀 雜杂 權权 條条 來来 極极 構构 溝沟 棗枣 櫃柜 標标 欄栏 攔拦 爛烂
+蘭兰 瀾澜 樹树 樣样 橋桥 嬌娇 矯矫 僑侨 喬乔 夢梦 樓楼 屢屡 簍篓 摟搂 婁娄 橫横 歡欢 勸劝 殲歼 畢毕 氣气 匯汇 彙汇 漢汉
+難难 嘆叹 歎叹 艱艰 灘滩 攤摊 癱瘫 沒没 淚泪 潔洁 淺浅 踐践 殘残 棧栈 箋笺 盞盏 湧涌 潤润 澀涩 溫温 灣湾 彎弯 蠻蛮 巒峦
+孿孪 攣挛 灤滦 滿满 濾滤 濫滥 藍蓝 籃篮 監监 艦舰 檻槛 滅灭 燈灯 靈灵 災灾 爐炉 廬庐 蘆芦 點点 煉炼 揀拣 熱热 勢势 藝艺
+爺爷 猶犹 獄狱 獵猎 貓猫 獻献 現现 電电 畫画 劃划 療疗 瘋疯 癢痒 癡痴 皺皱 蓋盖 盤盘 確确 礙碍 禮礼 禍祸 渦涡 窩窝 蝸蜗
+離离 種种 積积 稱称 穩稳 窮穷 竊窃 競竞 筆笔 筍笋 簡简 築筑 糧粮 羅罗 邏逻 蘿萝 籮箩 罰罚 聰聪 聯联 聲声 聽听 肅肃 蕭萧
+簫箫 嘯啸 膚肤 脅胁 勝胜 髒脏 臟脏 臘腊 蠟蜡 節节 蕪芜 蘇苏 囌苏 甦苏 蘋苹 範范 繭茧 薦荐 藥药 萊莱 獲获 穫获 慮虑 虛虚
+蟲虫 雖虽 蠶蚕 補补 裝装 裏里 裡里 復复 複复 觸触 趙赵 趕赶 躍跃 蹤踪 辭辞 遼辽 跡迹 蹟迹 鄰邻 釀酿 釋释 鬧闹 陰阴 際际
+陸陆 隨随 隱隐 隸隶 霧雾 靜静 黃黄 個个 箇个 們们 學学 舉举 與与 興兴 後后 從从 聳耸 發发 髮发 兒儿 幾几 機机 動动 國国
+體体 愛爱 曖暧 歷历 曆历 壓压 厲厉 勵励 礪砺 蠣蛎 廳厅 廣广 廠厂 廈厦 廚厨 兩两 倆俩 亂乱 爭争 淨净 掙挣 箏筝 猙狰 睜睁
+產产 薩萨 畝亩 處处 劇剧 號号 虧亏 衝冲 沖冲 萬万 業业 叢丛 嚴严 喪丧 豐丰 臨临 麗丽 儷俪 灑洒 烏乌 鄔邬 嗚呜 於于 雲云
+億亿 僅仅 侖仑 倫伦 淪沦 圇囵 價价 衆众 眾众 傘伞 傷伤 倀伥 偵侦 債债 傾倾 僂偻 僕仆 儲储 儼俨 儻傥 兌兑 脫脱 稅税 蛻蜕
+悅悦 內内 岡冈 剛刚 崗岗 冊册 塚冢 決决 況况 涼凉 減减 湊凑 凜凛 憑凭 擊击 芻刍 劉刘 刪删 別别 辦办 務务 勁劲 莖茎 徑径
+逕径 勞劳 撈捞 嘮唠 澇涝 癆痨 勳勋 勛勋 匱匮 醫医 華华 嘩哗 樺桦 協协 單单 禪禅 蟬蝉 嬋婵 憚惮 撣掸 殫殚 簞箪 盧卢 瀘泸
+卻却 厭厌 參参 滲渗 摻掺 雙双 敘叙 葉叶 嚇吓 員员 圓圆 韻韵 隕陨 吳吴 娛娱 呂吕 侶侣 吶呐 啓启 啟启 唄呗 啞哑 喲哟 嗇啬
+嘍喽 嘖啧 噓嘘 噴喷 噸吨 噥哝 嚀咛 囂嚣 囑嘱 囪囱 團团 糰团 園园 圖图 聖圣 塊块 堅坚 壇坛 罈坛 墊垫 墳坟 墮堕 壩坝 壯壮
+殼壳 壺壶 備备 夠够 夾夹 陝陕 俠侠 峽峡 狹狭 挾挟 莢荚 奪夺 奮奋 奧奥 妝妆 粧妆 婦妇 嫵妩 孫孙 遜逊 寧宁 擰拧 檸柠 獰狞
+濘泞 寶宝 審审 嬸婶 寬宽 尋寻 屬属 嶼屿 帥帅 懺忏 斷断 網网 腳脚 蝦虾 豬猪 燭烛 獨独 濁浊 螢萤 營营 瑩莹 榮荣 嶸嵘 熒荧
+蝨虱 蠍蝎 硯砚 臺台 檯台 颱台 亞亚 婭娅 鄒邹 襖袄 剝剥 斃毙 瀕濒 擯摈 殯殡 鬢鬓 撥拨 燦灿 襯衬 懲惩 恥耻 熾炽 櫥橱 雛雏
+礎础 蔥葱 躥蹿 竄窜 鄲郸 搗捣 盜盗 滌涤 澱淀 疊叠 犢犊 隊队 琺珐 礬矾 煩烦 糞粪 楓枫 稈秆 贛赣 擱搁 鞏巩 蠱蛊 剮剐 滾滚
+滬沪 毀毁 穢秽 葷荤 薊蓟 濺溅 澗涧 膠胶 繳缴 階阶 屆届 晉晋 燼烬 盡尽 儘尽 荊荆 廄厩 傑杰 墾垦 摳抠 褲裤 誇夸 巋岿 窺窥
+潰溃 懶懒 壘垒 籬篱 瀝沥 簾帘 擄掳 虜虏 祿禄 掄抡 邁迈 脈脉 瞞瞒 黴霉 膩腻 攆撵 瘧疟 潑泼 撲扑 樸朴 棲栖 淒凄 牽牵 潛潜
+牆墙 薔蔷 竅窍 寢寝 氫氢 瓊琼 趨趋 腎肾 濕湿 屍尸 豎竖 碩硕 慫怂 擻擞 瑣琐 獺獭 撻挞 擡抬 謄誊 烴烃 禿秃 塗涂 橢椭 窪洼
+襪袜 濰潍 甕瓮 撾挝 臥卧 塢坞 犧牺 襲袭 羨羡 憲宪 廂厢 攜携 釁衅 洶汹 癬癣 煙烟 豔艳 彥彦 瑤瑶 遙遥 窯窑 蔭荫 櫻樱 嬰婴
+傭佣 癰痈 踴踊 詠咏 籲吁 淵渊 粵粤 鄖郧 勻匀 蘊蕴 醞酝 攢攒 氈毡 佔占 蟄蛰 幀帧 職职 摯挚 幟帜 腫肿 矚瞩 樁桩 狀状 墜坠
+漬渍 囉啰 噠哒 採采 恆恒 朮术 鬱郁 佇伫 醃腌 裊袅 並并 併并 蠔蚝 準准 瀏浏 醜丑 瓏珑 瀟潇 灕漓 燉炖 愾忾 愴怆 慪怄 憮怃
+懌怿 懍懔 懣懑 懨恹 攖撄 殞殒 涇泾 淥渌 澠渑 滎荥 瀅滢 瀠潆 燜焖 獼猕 璣玑 瑋玮 瓔璎 癇痫 癟瘪 瘻瘘 皚皑 眥眦 矓眬 碭砀
+磯矶 礱砻 禎祯 稟禀 窶窭 竇窦 篳筚 簀箦 籟籁 糴籴 紬䌷 羥羟 翬翚 聹聍 脛胫 膾脍 艤舣 萇苌 蒞莅 蓀荪 蔞蒌 蕁荨 蕎荞 蕘荛
+薺荠 藹蔼 蘄蕲 虯虬 蛺蛱 蜆蚬 螄蛳 螻蝼 蟈蝈 蠑蝾 袞衮 褸褛 覷觑 諡谥 謚谥 豶豮 趲趱 躑踯 躚跹 軤轷 邐逦 酈郦 閌闶 闃阒
+闍阇 隉陧 雋隽 霽霁 靦腼 韞韫 頦颏 餑饽 餷馇 饈馐 騖骛 騫骞 驪骊 髏髅 鬩阋 魘魇 鮐鲐 鯗鲞 鱒鳟 鵂鸺 鵜鹈 鶿鹚 鷦鹪 鸝鹂
+黲黪 黷黩 鼴鼹 齏齑
+`
+
+// t2sOnlyPairs 只用于繁转简：这些繁体字转换为简体后，简体字在多数语境下
+// 对应另一个繁体字（或本身就是通用写法），简转繁时不以它们为默认结果
+const t2sOnlyPairs = `
+誌志 譁哗 鎔熔 纍累 繫系 係系 紮扎 緻致 闆板 闢辟 輓挽 颳刮 餵喂 齣出 麵面 麪面 徵征 兇凶 嚮向 巖岩 鬆松 瞭了 週周 夥伙
+淩凌 汙污 汚污 菸烟 艷艳 遊游 禦御 嶽岳 竈灶 劄札 喫吃 祇只 衹只 隻只 彆别 僱雇 託托 佈布 倣仿 傢家 剋克 鬍胡 衚胡 纔才
+綵彩 唸念 稜棱 蒐搜 捨舍 薑姜 峯峰 羣群 迴回 癒愈 痲麻 蔔卜 瀋沈 嚥咽 慾欲 慼戚 簷檐 祐佑 衊蔑 麴曲 氾泛 穀谷 錶表 鞦秋
+韆千 註注 捲卷 著着 藉借 摺折 糉粽 痺痹 鬨哄 卹恤 昇升 陞升 盃杯 製制 彫雕 鵰雕 籐藤 鍊炼 燻熏 睏困 綑捆 蝟猬 谿溪 貍狸
+醱酦 隄堤 妳你 牠它
+`
@@ -0,0 +1,709 @@
+package zhconv
+
+// 词组表每行一项，格式为 "原词 转换结果"。词组优先于单字，按最长匹配使用；
+// 结果与原词相同的条目用于固定分词，避免相邻字被错误地组成词组
+// （如 "明白" 阻止 "白发生" 被转换成 "白髮生"）
+
+// s2tPhrases 是简转繁的词组表，处理一个简体字对应多个繁体字的情况
+const s2tPhrases = `
+头发 頭髮
+理发 理髮
+白发 白髮
+发型 髮型
+染发 染髮
+发夹 髮夾
+假发 假髮
+短发 短髮
+长发 長髮
+卷发 捲髮
+脱发 脫髮
+发廊 髮廊
+毛发 毛髮
+护发 護髮
+洗发 洗髮
+美发 美髮
+剪发 剪髮
+秀发 秀髮
+烫发 燙髮
+发丝 髮絲
+发际 髮際
+发胶 髮膠
+须发 鬚髮
+一发千钧 一髮千鈞
+令人发指 令人髮指
+明白 明白
+表白 表白
+坦白 坦白
+告白 告白
+空白 空白
+完美 完美
+保护 保護
+优秀 優秀
+假发票 假發票
+生长发育 生長發育
+成长发育 成長發育
+增长发展 增長發展
+成长发展 成長發展
+皇后 皇后
+王后 王后
+太后 太后
+母后 母后
+天后 天后
+影后 影后
+歌后 歌后
+后妃 后妃
+后土 后土
+后羿 后羿
+面条 麵條
+面包 麵包
+面粉 麵粉
+面食 麵食
+面馆 麵館
+面团 麵團
+拉面 拉麵
+泡面 泡麵
+挂面 掛麵
+汤面 湯麵
+炒面 炒麵
+凉面 涼麵
+意面 意麵
+方便面 方便麵
+地面 地面
+表面 表面
+里面 裏面
+外面 外面
+方面 方面
+前面 前面
+后面 後面
+上面 上面
+下面 下面
+对面 對面
+全面 全面
+见面 見面
+画面 畫面
+场面 場面
+干净 乾淨
+干燥 乾燥
+干旱 乾旱
+干脆 乾脆
+干枯 乾枯
+干杯 乾杯
+干货 乾貨
+干粮 乾糧
+干瘪 乾癟
+干笑 乾笑
+干咳 乾咳
+干妈 乾媽
+干爹 乾爹
+干儿子 乾兒子
+干女儿 乾女兒
+干涩 乾澀
+干冰 乾冰
+干电池 乾電池
+干洗 乾洗
+干果 乾果
+干草 乾草
+干巴巴 乾巴巴
+干瞪眼 乾瞪眼
+干着急 乾着急
+晒干 曬乾
+烘干 烘乾
+擦干 擦乾
+风干 風乾
+吹干 吹乾
+晾干 晾乾
+口干 口乾
+饼干 餅乾
+肉干 肉乾
+豆腐干 豆腐乾
+葡萄干 葡萄乾
+一干二净 一乾二淨
+外强中干 外強中乾
+干涉 干涉
+干扰 干擾
+干预 干預
+若干 若干
+相干 相干
+干戈 干戈
+天干 天干
+干支 干支
+干系 干係
+公里 公里
+千里 千里
+英里 英里
+华里 華里
+万里 萬里
+里程 里程
+邻里 鄰里
+故里 故里
+乡里 鄉里
+里弄 里弄
+里长 里長
+台风 颱風
+台球 檯球
+柜台 櫃檯
+吧台 吧檯
+台灯 檯燈
+台面 檯面
+写字台 寫字檯
+梳妆台 梳妝檯
+一只 一隻
+两只 兩隻
+三只 三隻
+四只 四隻
+五只 五隻
+几只 幾隻
+每只 每隻
+这只 這隻
+那只 那隻
+船只 船隻
+只身 隻身
+只言片语 隻言片語
+形单影只 形單影隻
+这只是 這只是
+那只是 那只是
+这只能 這只能
+那只能 那只能
+这只有 這只有
+那只有 那只有
+这只会 這只會
+那只会 那只會
+复杂 複雜
+重复 重複
+复制 複製
+复印 複印
+复习 複習
+复数 複數
+复合 複合
+复述 複述
+复核 複核
+复式 複式
+复眼 複眼
+复利 複利
+复查 複查
+复赛 複賽
+复读 複讀
+复写 複寫
+复诊 複診
+复选 複選
+繁复 繁複
+反复 反覆
+答复 答覆
+回复 回覆
+放松 放鬆
+轻松 輕鬆
+宽松 寬鬆
+蓬松 蓬鬆
+稀松 稀鬆
+松开 鬆開
+松散 鬆散
+松懈 鬆懈
+松弛 鬆弛
+松动 鬆動
+松紧 鬆緊
+松口 鬆口
+松绑 鬆綁
+松软 鬆軟
+肉松 肉鬆
+制作 製作
+制造 製造
+制品 製品
+制成 製成
+制片 製片
+制药 製藥
+制冷 製冷
+制图 製圖
+制衣 製衣
+制版 製版
+制剂 製劑
+绘制 繪製
+录制 錄製
+研制 研製
+配制 配製
+炼制 煉製
+监制 監製
+特制 特製
+定制 定製
+印制 印製
+摄制 攝製
+缝制 縫製
+仿制 仿製
+精制 精製
+腌制 醃製
+酿制 釀製
+炮制 炮製
+日历 日曆
+月历 月曆
+年历 年曆
+挂历 掛曆
+台历 檯曆
+历法 曆法
+历书 曆書
+农历 農曆
+阳历 陽曆
+阴历 陰曆
+公历 公曆
+西历 西曆
+旧历 舊曆
+皇历 皇曆
+黄历 黃曆
+万年历 萬年曆
+批准 批准
+核准 核准
+获准 獲准
+照准 照准
+准许 准許
+准予 准予
+准将 准將
+准考证 准考證
+不准 不准
+不准确 不準確
+冲洗 沖洗
+冲水 沖水
+冲泡 沖泡
+冲凉 沖涼
+冲澡 沖澡
+冲刷 沖刷
+冲淡 沖淡
+冲茶 沖茶
+冲咖啡 沖咖啡
+冲印 沖印
+冲积 沖積
+冲走 沖走
+冲服 沖服
+冲马桶 沖馬桶
+冲厕所 沖廁所
+兴冲冲 興沖沖
+怒气冲冲 怒氣沖沖
+钟情 鍾情
+钟爱 鍾愛
+钟馗 鍾馗
+钟表 鐘錶
+手表 手錶
+怀表 懷錶
+腕表 腕錶
+秒表 秒錶
+电表 電錶
+水表 水錶
+码表 碼錶
+名表 名錶
+表带 錶帶
+表盘 錶盤
+表链 錶鏈
+表壳 錶殼
+代表 代表
+人云亦云 人云亦云
+云云 云云
+古人云 古人云
+尽管 儘管
+尽量 儘量
+尽快 儘快
+尽早 儘早
+尽可能 儘可能
+收获 收穫
+卷入 捲入
+卷起 捲起
+卷走 捲走
+卷曲 捲曲
+卷尺 捲尺
+卷帘 捲簾
+卷款 捲款
+卷烟 捲菸
+卷心菜 捲心菜
+卷铺盖 捲鋪蓋
+卷土重来 捲土重來
+席卷 席捲
+春卷 春捲
+花卷 花捲
+蛋卷 蛋捲
+内卷 內捲
+龙卷风 龍捲風
+特征 特徵
+象征 象徵
+表征 表徵
+体征 體徵
+病征 病徵
+征求 徵求
+征收 徵收
+征兵 徵兵
+征集 徵集
+征稿 徵稿
+征婚 徵婚
+征召 徵召
+征询 徵詢
+征文 徵文
+征税 徵稅
+征用 徵用
+征兆 徵兆
+征信 徵信
+征候 徵候
+征象 徵象
+北斗 北斗
+漏斗 漏斗
+熨斗 熨斗
+泰斗 泰斗
+烟斗 煙斗
+星斗 星斗
+筋斗 筋斗
+跟斗 跟斗
+翻斗 翻斗
+斗笠 斗笠
+斗篷 斗篷
+斗胆 斗膽
+斗室 斗室
+斗拱 斗拱
+斗转星移 斗轉星移
+车载斗量 車載斗量
+才高八斗 才高八斗
+稻谷 稻穀
+五谷 五穀
+谷物 穀物
+谷子 穀子
+谷类 穀類
+谷雨 穀雨
+谷仓 穀倉
+谷粒 穀粒
+谷壳 穀殼
+小丑 小丑
+丑角 丑角
+丑时 丑時
+丑年 丑年
+子丑寅卯 子丑寅卯
+朴刀 朴刀
+朴树 朴樹
+前仆后继 前仆後繼
+借口 藉口
+凭借 憑藉
+借故 藉故
+借以 藉以
+借此 藉此
+借由 藉由
+舍得 捨得
+舍不得 捨不得
+不舍 不捨
+难舍 難捨
+舍弃 捨棄
+施舍 施捨
+割舍 割捨
+取舍 取捨
+舍命 捨命
+舍身 捨身
+舍己 捨己
+舍近求远 捨近求遠
+舍生取义 捨生取義
+四舍五入 四捨五入
+依依不舍 依依不捨
+恋恋不舍 戀戀不捨
+锲而不舍 鍥而不捨
+酒坛 酒罈
+坛子 罈子
+词汇 詞彙
+字汇 字彙
+语汇 語彙
+汇编 彙編
+汇总 彙總
+汇整 彙整
+浓郁 濃郁
+馥郁 馥郁
+秋千 鞦韆
+荡秋千 盪鞦韆
+精致 精緻
+细致 細緻
+雅致 雅緻
+别致 別緻
+景致 景緻
+标致 標緻
+致密 緻密
+凶手 兇手
+凶恶 兇惡
+凶残 兇殘
+凶狠 兇狠
+凶猛 兇猛
+凶器 兇器
+凶案 兇案
+凶杀 兇殺
+凶暴 兇暴
+凶巴巴 兇巴巴
+凶神恶煞 兇神惡煞
+行凶 行兇
+帮凶 幫兇
+元凶 元兇
+注册 註冊
+注释 註釋
+注解 註解
+注销 註銷
+注明 註明
+注脚 註腳
+注记 註記
+备注 備註
+附注 附註
+批注 批註
+标注 標註
+脚注 腳註
+旅游 旅遊
+游戏 遊戲
+游客 遊客
+游玩 遊玩
+游览 遊覽
+游行 遊行
+游乐 遊樂
+游历 遊歷
+游记 遊記
+游子 遊子
+游荡 遊蕩
+游艇 遊艇
+游人 遊人
+游说 遊說
+游园 遊園
+游船 遊船
+游轮 遊輪
+游山玩水 遊山玩水
+游刃有余 遊刃有餘
+游手好闲 遊手好閒
+导游 導遊
+郊游 郊遊
+周游 周遊
+云游 雲遊
+出游 出遊
+漫游 漫遊
+夜游 夜遊
+神游 神遊
+春游 春遊
+手游 手遊
+网游 網遊
+端游 端遊
+页游 頁遊
+桌游 桌遊
+胡须 鬍鬚
+胡子 鬍子
+触须 觸鬚
+须眉 鬚眉
+标签 標籤
+书签 書籤
+抽签 抽籤
+求签 求籤
+牙签 牙籤
+竹签 竹籤
+纤夫 縴夫
+拉纤 拉縴
+回旋 迴旋
+回廊 迴廊
+回响 迴響
+回荡 迴盪
+回避 迴避
+回转 迴轉
+回圈 迴圈
+迂回 迂迴
+巡回 巡迴
+轮回 輪迴
+峰回路转 峰迴路轉
+关系 關係
+没关系 沒關係
+系数 係數
+联系 聯繫
+维系 維繫
+系上 繫上
+系好 繫好
+系住 繫住
+系着 繫着
+系鞋带 繫鞋帶
+划船 划船
+划算 划算
+划桨 划槳
+划拳 划拳
+划水 划水
+划不来 划不來
+划龙舟 划龍舟
+风采 風采
+神采 神采
+文采 文采
+丰采 丰采
+兴高采烈 興高采烈
+无精打采 無精打采
+茶几 茶几
+几案 几案
+咸丰 咸豐
+咸阳 咸陽
+海淀 海淀
+防御 防禦
+抵御 抵禦
+御寒 禦寒
+御敌 禦敵
+山岳 山嶽
+五岳 五嶽
+佣金 佣金
+沈阳 瀋陽
+占卜 占卜
+占星 占星
+占卦 占卦
+萝卜 蘿蔔
+胡萝卜 胡蘿蔔
+老板 老闆
+家伙 傢伙
+香烟 香菸
+烟草 菸草
+抽烟 抽菸
+吸烟 吸菸
+戒烟 戒菸
+烟瘾 菸癮
+烟头 菸頭
+痊愈 痊癒
+治愈 治癒
+愈合 癒合
+麻痹 麻痺
+咽下 嚥下
+吞咽 吞嚥
+狼吞虎咽 狼吞虎嚥
+食欲 食慾
+性欲 性慾
+情欲 情慾
+欲望 慾望
+贪欲 貪慾
+物欲 物慾
+屋檐 屋簷
+房檐 房簷
+帽檐 帽簷
+酒曲 酒麴
+泛滥 氾濫
+委托 委託
+托付 託付
+拜托 拜託
+寄托 寄託
+推托 推託
+托辞 託辭
+信托 信託
+托管 託管
+仿佛 彷彿
+别扭 彆扭
+杂志 雜誌
+标志 標誌
+日志 日誌
+诬蔑 誣衊
+奏折 奏摺
+折叠 摺疊
+折扇 摺扇
+折纸 摺紙
+存折 存摺
+扎实 紮實
+驻扎 駐紮
+包扎 包紮
+扎营 紮營
+扎根 紮根
+扎染 紮染
+扎辫子 紮辮子
+卤肉 滷肉
+卤味 滷味
+卤蛋 滷蛋
+喂养 餵養
+喂食 餵食
+喂奶 餵奶
+喂饭 餵飯
+喂狗 餵狗
+吁吁 吁吁
+`
+
+// t2sPhrases 是繁转简的词组表，处理默认字表会转错的词
+const t2sPhrases = `
+乾隆 乾隆
+乾坤 乾坤
+乾卦 乾卦
+著名 著名
+著作 著作
+著者 著者
+著稱 著称
+著述 著述
+著書 著书
+著錄 著录
+著有 著有
+所著 所著
+名著 名著
+原著 原著
+巨著 巨著
+論著 论著
+編著 编著
+譯著 译著
+專著 专著
+遺著 遗著
+新著 新著
+拙著 拙著
+顯著 显著
+卓著 卓著
+昭著 昭著
+土著 土著
+瞭望 瞭望
+狼藉 狼藉
+慰藉 慰藉
+枕藉 枕藉
+蘊藉 蕴藉
+反覆 反复
+答覆 答复
+回覆 回复
+`
+
+// twPhrases 是大陆与台湾的常用词汇差异，两侧都使用 s2t 的繁体写法；
+// 台湾转大陆时同一个台湾词汇对应多个大陆词汇的，取排在前面的一项
+const twPhrases = `
+軟件 軟體
+硬件 硬體
+視頻 影片
+網絡 網路
+信息 資訊
+鼠標 滑鼠
+內存 記憶體
+硬盤 硬碟
+服務器 伺服器
+數據庫 資料庫
+數據 資料
+文件夾 資料夾
+打印機 印表機
+打印 列印
+屏幕 螢幕
+默認 預設
+界面 介面
+接口 介面
+程序員 程式設計師
+程序 程式
+法律程序 法律程序
+訴訟程序 訴訟程序
+函數 函式
+變量 變數
+字符串 字串
+字符 字元
+代碼 程式碼
+源代碼 原始碼
+菜單 選單
+鏈接 連結
+博客 部落格
+短信 簡訊
+移動電話 行動電話
+筆記本電腦 筆記型電腦
+操作系統 作業系統
+光標 游標
+激光 雷射
+寬帶 寬頻
+在線 線上
+質量 品質
+出租車 計程車
+公交車 公車
+自行車 腳踏車
+摩托車 機車
+新西蘭 紐西蘭
+意大利 義大利
+奧巴馬 歐巴馬
+`
+
+// twOnlyPhrases 只用于大陆转台湾；反方向在大陆也有其他含义，不做转换
+const twOnlyPhrases = `
+計算機 電腦
+悉尼 雪梨
+方便麵 泡麵
+`
+
+// twVariants 和 hkVariants 是地区异体字表，每项为 "标准繁体 地区写法"
+const twVariants = `
+裏裡 爲為 僞偽 啓啟 衆眾 着著 綫線 麪麵 羣群 峯峰 鷄雞 牀床 鉢缽
+`
+
+const hkVariants = `
+爲為 僞偽 衆眾 說説 脫脱 稅税 悅悦 閱閲 銳鋭 兌兑 蛻蜕 內内 溫温 線綫 裡裏 啟啓
+`
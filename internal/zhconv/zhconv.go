@@ -0,0 +1,214 @@
+// Package zhconv 实现简体中文与繁体中文之间的转换。
+// 转换方式与 OpenCC 相同：每种转换由若干个词典阶段串联而成，
+// 每个阶段先按最长匹配查找词组，找不到时再逐字转换
+package zhconv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// 转换配置，名称与 OpenCC 的配置文件一致
+const (
+	S2T   = "s2t"   // 简体到繁体
+	S2TW  = "s2tw"  // 简体到台湾正体
+	S2TWP = "s2twp" // 简体到台湾正体，并转换常用词汇
+	S2HK  = "s2hk"  // 简体到香港繁体
+	T2S   = "t2s"   // 繁体到简体
+	TW2S  = "tw2s"  // 台湾正体到简体
+	TW2SP = "tw2sp" // 台湾正体到简体，并转换常用词汇
+	HK2S  = "hk2s"  // 香港繁体到简体
+)
+
+// Converter 按配置转换文本，可以在多个 goroutine 中共用
+type Converter struct {
+	config string
+	stages []*dictionary
+}
+
+// dictionary 是转换的一个阶段
+type dictionary struct {
+	phrases map[string]string
+	chars   map[rune]rune
+	maxLen  int // 最长词组的字数
+}
+
+var (
+	loadOnce sync.Once
+	configs  map[string][]*dictionary
+)
+
+// New 返回指定配置的转换器
+func New(config string) (*Converter, error) {
+	loadOnce.Do(load)
+	stages, ok := configs[strings.ToLower(config)]
+	if !ok {
+		return nil, fmt.Errorf("unknown conversion %q (available: %s)", config, strings.Join(Configs(), ", "))
+	}
+	return &Converter{config: strings.ToLower(config), stages: stages}, nil
+}
+
+// Configs 返回所有可用的转换配置
+func Configs() []string {
+	loadOnce.Do(load)
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Config 返回转换器的配置名
+func (c *Converter) Config() string {
+	return c.config
+}
+
+// Convert 转换一段文本
+func (c *Converter) Convert(text string) string {
+	for _, stage := range c.stages {
+		text = stage.convert(text)
+	}
+	return text
+}
+
+// convert 从左到右扫描文本，优先替换最长的词组
+func (d *dictionary) convert(text string) string {
+	var out strings.Builder
+	out.Grow(len(text))
+	for len(text) > 0 {
+		if size, replacement := d.matchPhrase(text); size > 0 {
+			out.WriteString(replacement)
+			text = text[size:]
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(text)
+		if converted, ok := d.chars[r]; ok {
+			r = converted
+		}
+		out.WriteRune(r)
+		text = text[size:]
+	}
+	return out.String()
+}
+
+// matchPhrase 查找以 text 开头的最长词组，返回词组的字节长度和转换结果
+func (d *dictionary) matchPhrase(text string) (int, string) {
+	if len(d.phrases) == 0 {
+		return 0, ""
+	}
+	// 先找出最多 maxLen 个字的边界，再从长到短查找
+	ends := make([]int, 0, d.maxLen)
+	for i := range text {
+		if i > 0 {
+			ends = append(ends, i)
+		}
+		if len(ends) == d.maxLen {
+			break
+		}
+	}
+	if len(ends) < d.maxLen {
+		ends = append(ends, len(text))
+	}
+	for i := len(ends) - 1; i >= 0; i-- {
+		if replacement, ok := d.phrases[text[:ends[i]]]; ok {
+			return ends[i], replacement
+		}
+	}
+	return 0, ""
+}
+
+// load 解析内置词典并组装各个配置
+func load() {
+	s2tChars := map[rune]rune{}
+	t2sChars := map[rune]rune{}
+	for _, pair := range splitPairs(charPairs) {
+		trad, simp := pair[0], pair[1]
+		if _, ok := s2tChars[simp]; !ok {
+			s2tChars[simp] = trad
+		}
+		t2sChars[trad] = simp
+	}
+	for _, pair := range splitPairs(t2sOnlyPairs) {
+		t2sChars[pair[0]] = pair[1]
+	}
+
+	s2t := newDictionary(parsePhrases(s2tPhrases, false), s2tChars)
+	t2s := newDictionary(parsePhrases(t2sPhrases, false), t2sChars)
+
+	twForward := parsePhrases(twPhrases, false)
+	for from, to := range parsePhrases(twOnlyPhrases, false) {
+		twForward[from] = to
+	}
+	tw := newDictionary(nil, variantChars(twVariants))
+	hk := newDictionary(nil, variantChars(hkVariants))
+
+	configs = map[string][]*dictionary{
+		S2T:   {s2t},
+		S2TW:  {s2t, tw},
+		S2TWP: {s2t, newDictionary(twForward, nil), tw},
+		S2HK:  {s2t, hk},
+		T2S:   {t2s},
+		// 繁转简字表已经包含各地区的异体字，所以地区配置与 t2s 共用字表
+		TW2S:  {t2s},
+		TW2SP: {newDictionary(parsePhrases(twPhrases, true), nil), t2s},
+		HK2S:  {t2s},
+	}
+}
+
+func newDictionary(phrases map[string]string, chars map[rune]rune) *dictionary {
+	d := &dictionary{phrases: phrases, chars: chars}
+	for phrase := range phrases {
+		if n := utf8.RuneCountInString(phrase); n > d.maxLen {
+			d.maxLen = n
+		}
+	}
+	return d
+}
+
+// splitPairs 解析 "繁简" 形式的字对照表
+func splitPairs(table string) [][2]rune {
+	var pairs [][2]rune
+	for _, field := range strings.Fields(table) {
+		runes := []rune(field)
+		if len(runes) != 2 {
+			panic(fmt.Sprintf("zhconv: invalid character pair %q", field))
+		}
+		pairs = append(pairs, [2]rune{runes[0], runes[1]})
+	}
+	return pairs
+}
+
+// variantChars 解析 "标准繁体 地区写法" 形式的异体字表
+func variantChars(table string) map[rune]rune {
+	chars := map[rune]rune{}
+	for _, pair := range splitPairs(table) {
+		chars[pair[0]] = pair[1]
+	}
+	return chars
+}
+
+// parsePhrases 解析词组表；reverse 为真时交换方向，同一个结果对应多个原词时取第一个
+func parsePhrases(table string, reverse bool) map[string]string {
+	phrases := map[string]string{}
+	for _, line := range strings.Split(table, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			panic(fmt.Sprintf("zhconv: invalid phrase entry %q", line))
+		}
+		from, to := fields[0], fields[1]
+		if reverse {
+			from, to = to, from
+		}
+		if _, ok := phrases[from]; !ok {
+			phrases[from] = to
+		}
+	}
+	return phrases
+}
@@ -0,0 +1,56 @@
+package zhconv
+
+import "testing"
+
+// TestConvert tests phrase-level conversion and regional variants for each config.
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		config, in, want string
+	}{
+		{S2T, "我的头发很长，明白发生了什么", "我的頭髮很長，明白發生了什麼"},
+		{S2T, "这只猫只是饿了，里面有面包", "這隻貓只是餓了，裏面有麵包"},
+		{S2T, "干净的饼干，干什么都行", "乾淨的餅乾，幹什麼都行"},
+		{S2T, "皇后说以后再说", "皇后說以後再說"},
+		{S2TW, "里面为什么着急", "裡面為什麼著急"},
+		{S2TWP, "这个软件的视频在网络上", "這個軟體的影片在網路上"},
+		{S2TWP, "计算机程序", "電腦程式"},
+		{S2HK, "为什么说脱离了内容", "為什麼説脱離了内容"},
+		{T2S, "著名作家的著作很顯著，他著急穿著衣服", "著名作家的著作很显著，他着急穿着衣服"},
+		{T2S, "乾淨的乾隆皇帝", "干净的乾隆皇帝"},
+		{TW2S, "裡面為什麼", "里面为什么"},
+		{TW2SP, "這個軟體的影片在網路上", "这个软件的视频在网络上"},
+		{HK2S, "説脱離了内容", "说脱离了内容"},
+		{T2S, "English 與 123 不變", "English 与 123 不变"},
+	}
+	for _, tt := range tests {
+		converter, err := New(tt.config)
+		if err != nil {
+			t.Fatalf("New(%q) returned error: %v", tt.config, err)
+		}
+		if got := converter.Convert(tt.in); got != tt.want {
+			t.Errorf("%s: Convert(%q) = %q, want %q", tt.config, tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestRoundTrip tests that common simplified text survives s2t followed by t2s.
+func TestRoundTrip(t *testing.T) {
+	s2t, _ := New(S2T)
+	t2s, _ := New(T2S)
+	for _, text := range []string{
+		"我们今天来聊一聊这个游戏的剧情，它的画面非常精致",
+		"尽管复杂，但是只要反复练习就会有收获",
+		"后面那只猫的头发都干了",
+	} {
+		if got := t2s.Convert(s2t.Convert(text)); got != text {
+			t.Errorf("round trip of %q = %q", text, got)
+		}
+	}
+}
+
+// TestUnknownConfig tests that an unknown config name is rejected.
+func TestUnknownConfig(t *testing.T) {
+	if _, err := New("s2x"); err == nil {
+		t.Error("New(\"s2x\") returned no error")
+	}
+}